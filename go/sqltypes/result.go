@@ -21,6 +21,8 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
 )
 
 // Result represents a query result.
@@ -220,6 +222,45 @@ func (result *Result) AppendResult(src *Result) {
 	result.Rows = append(result.Rows, src.Rows...)
 }
 
+// MergeResults concatenates the rows of results returned by the shards of a fan-out query.
+// Results without fields, like the ones of DMLs, are merged with any other result.
+// RowsAffected are summed, and the last non-zero InsertID is kept, like AppendResult does.
+// An error is returned if two results have different fields.
+func MergeResults(results []*Result) (*Result, error) {
+	merged := &Result{}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		if len(merged.Fields) != 0 && len(result.Fields) != 0 && !fieldsCompatible(merged.Fields, result.Fields) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "cannot merge results with different fields: %v and %v", merged.Fields, result.Fields)
+		}
+		if merged.Fields == nil {
+			merged.Fields = result.Fields
+		}
+		merged.RowsAffected += result.RowsAffected
+		if result.InsertID != 0 {
+			merged.InsertID = result.InsertID
+		}
+		merged.Rows = append(merged.Rows, result.Rows...)
+	}
+	return merged, nil
+}
+
+// fieldsCompatible compares two arrays of fields by name and type. Unlike FieldsEqual,
+// it ignores the other metadata, which can differ between shards.
+func fieldsCompatible(f1, f2 []*querypb.Field) bool {
+	if len(f1) != len(f2) {
+		return false
+	}
+	for i, f := range f1 {
+		if f.Name != f2[i].Name || f.Type != f2[i].Type {
+			return false
+		}
+	}
+	return true
+}
+
 // Named returns a NamedResult based on this struct
 func (result *Result) Named() *NamedResult {
 	return ToNamedResult(result)
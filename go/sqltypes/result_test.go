@@ -18,6 +18,7 @@ package sqltypes
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -346,3 +347,76 @@ func TestAppendResult(t *testing.T) {
 		t.Errorf("Got:\n%#v, want:\n%#v", result, want)
 	}
 }
+
+func TestMergeResults(t *testing.T) {
+	fields := []*querypb.Field{{
+		Name: "id",
+		Type: Int64,
+	}, {
+		Name: "name",
+		Type: VarChar,
+	}}
+	shard1 := &Result{
+		Fields:       fields,
+		RowsAffected: 1,
+		Rows: [][]Value{
+			{TestValue(Int64, "1"), TestValue(VarChar, "a")},
+		},
+	}
+	shard2 := &Result{
+		// the other metadata of the fields can differ between shards.
+		Fields: []*querypb.Field{{
+			Name:     "id",
+			Type:     Int64,
+			Database: "vt_ks_80-",
+		}, {
+			Name: "name",
+			Type: VarChar,
+		}},
+		RowsAffected: 2,
+		InsertID:     5,
+		Rows: [][]Value{
+			{TestValue(Int64, "2"), TestValue(VarChar, "b")},
+			{TestValue(Int64, "3"), MakeTrusted(Null, nil)},
+		},
+	}
+	want := &Result{
+		Fields:       fields,
+		RowsAffected: 3,
+		InsertID:     5,
+		Rows: [][]Value{
+			{TestValue(Int64, "1"), TestValue(VarChar, "a")},
+			{TestValue(Int64, "2"), TestValue(VarChar, "b")},
+			{TestValue(Int64, "3"), MakeTrusted(Null, nil)},
+		},
+	}
+	got, err := MergeResults([]*Result{shard1, nil, {}, shard2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("MergeResults:\n%#v, want\n%#v", got, want)
+	}
+
+	got, err = MergeResults(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(&Result{}) {
+		t.Errorf("MergeResults(nil): %#v, want empty result", got)
+	}
+
+	mismatch := &Result{
+		Fields: []*querypb.Field{{
+			Name: "id",
+			Type: VarChar,
+		}, {
+			Name: "name",
+			Type: VarChar,
+		}},
+	}
+	_, err = MergeResults([]*Result{shard1, mismatch})
+	if err == nil || !strings.Contains(err.Error(), "cannot merge results with different fields") {
+		t.Errorf("MergeResults with mismatched fields: %v, want a field mismatch error", err)
+	}
+}
@@ -154,10 +154,19 @@ type Session struct {
 	// read_after_write tracks the ReadAfterWrite settings for this session.
 	ReadAfterWrite *ReadAfterWrite `protobuf:"bytes,20,opt,name=read_after_write,json=readAfterWrite,proto3" json:"read_after_write,omitempty"`
 	// DDL strategy
-	DDLStrategy          string   `protobuf:"bytes,21,opt,name=DDLStrategy,proto3" json:"DDLStrategy,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DDLStrategy string `protobuf:"bytes,21,opt,name=DDLStrategy,proto3" json:"DDLStrategy,omitempty"`
+	// advisory_lock keeps track of the advisory locks held by the session
+	// and the number of times each of them was acquired.
+	AdvisoryLock map[string]int64 `protobuf:"bytes,22,rep,name=advisory_lock,json=advisoryLock,proto3" json:"advisory_lock,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// release_locks_on_commit releases the advisory locks acquired during
+	// a transaction when the transaction is committed.
+	ReleaseLocksOnCommit bool `protobuf:"varint,23,opt,name=release_locks_on_commit,json=releaseLocksOnCommit,proto3" json:"release_locks_on_commit,omitempty"`
+	// tx_advisory_lock keeps track of the advisory locks acquired during the
+	// current transaction and the number of times each of them was acquired.
+	TxAdvisoryLock       map[string]int64 `protobuf:"bytes,24,rep,name=tx_advisory_lock,json=txAdvisoryLock,proto3" json:"tx_advisory_lock,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *Session) Reset()         { *m = Session{} }
@@ -325,6 +334,27 @@ func (m *Session) GetDDLStrategy() string {
 	return ""
 }
 
+func (m *Session) GetAdvisoryLock() map[string]int64 {
+	if m != nil {
+		return m.AdvisoryLock
+	}
+	return nil
+}
+
+func (m *Session) GetReleaseLocksOnCommit() bool {
+	if m != nil {
+		return m.ReleaseLocksOnCommit
+	}
+	return false
+}
+
+func (m *Session) GetTxAdvisoryLock() map[string]int64 {
+	if m != nil {
+		return m.TxAdvisoryLock
+	}
+	return nil
+}
+
 type Session_ShardSession struct {
 	Target        *query.Target         `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
 	TransactionId int64                 `protobuf:"varint,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
@@ -1073,7 +1103,9 @@ func init() {
 	proto.RegisterEnum("vtgate.TransactionMode", TransactionMode_name, TransactionMode_value)
 	proto.RegisterEnum("vtgate.CommitOrder", CommitOrder_name, CommitOrder_value)
 	proto.RegisterType((*Session)(nil), "vtgate.Session")
+	proto.RegisterMapType((map[string]int64)(nil), "vtgate.Session.AdvisoryLockEntry")
 	proto.RegisterMapType((map[string]string)(nil), "vtgate.Session.SystemVariablesEntry")
+	proto.RegisterMapType((map[string]int64)(nil), "vtgate.Session.TxAdvisoryLockEntry")
 	proto.RegisterMapType((map[string]*query.BindVariable)(nil), "vtgate.Session.UserDefinedVariablesEntry")
 	proto.RegisterType((*Session_ShardSession)(nil), "vtgate.Session.ShardSession")
 	proto.RegisterType((*ReadAfterWrite)(nil), "vtgate.ReadAfterWrite")
@@ -1092,90 +1124,94 @@ func init() {
 func init() { proto.RegisterFile("vtgate.proto", fileDescriptor_aab96496ceaf1ebb) }
 
 var fileDescriptor_aab96496ceaf1ebb = []byte{
-	// 1346 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6e, 0x1b, 0xb7,
-	0x12, 0xce, 0xea, 0x5f, 0xa3, 0xbf, 0x35, 0x6d, 0xe7, 0x6c, 0x7c, 0x72, 0xce, 0x11, 0x94, 0x04,
-	0x51, 0x72, 0x0a, 0xa9, 0x75, 0xd1, 0x36, 0x28, 0x5a, 0xb4, 0xb6, 0xac, 0xa4, 0x0a, 0xec, 0xc8,
-	0xa5, 0x64, 0x1b, 0x28, 0x5a, 0x2c, 0x68, 0x2d, 0x2d, 0x13, 0x96, 0x97, 0x0a, 0x49, 0xc9, 0xd5,
-	0x53, 0xf4, 0xbe, 0x2f, 0xd0, 0x9b, 0xde, 0xf7, 0x1d, 0x7a, 0xd7, 0xc7, 0xe9, 0x5d, 0x41, 0xee,
-	0xae, 0xb4, 0x52, 0xdc, 0xc6, 0x49, 0x90, 0x1b, 0x61, 0x67, 0xbe, 0xe1, 0x70, 0x38, 0xdf, 0x0c,
-	0x87, 0x82, 0xe2, 0x54, 0x0d, 0x89, 0xa2, 0x8d, 0xb1, 0xe0, 0x8a, 0xa3, 0x4c, 0x20, 0x6d, 0xd9,
-	0xa7, 0xcc, 0x1f, 0xf1, 0xa1, 0x47, 0x14, 0x09, 0x90, 0xad, 0xc2, 0xcb, 0x09, 0x15, 0xb3, 0x50,
-	0x28, 0x2b, 0x3e, 0xe6, 0x71, 0x70, 0xaa, 0xc4, 0x78, 0x10, 0x08, 0xb5, 0x3f, 0x01, 0xb2, 0x3d,
-	0x2a, 0x25, 0xe3, 0x3e, 0x7a, 0x00, 0x65, 0xe6, 0xbb, 0x4a, 0x10, 0x5f, 0x92, 0x81, 0x62, 0xdc,
-	0x77, 0xac, 0xaa, 0x55, 0xcf, 0xe1, 0x12, 0xf3, 0xfb, 0x0b, 0x25, 0x6a, 0x41, 0x59, 0x9e, 0x13,
-	0xe1, 0xb9, 0x32, 0x58, 0x27, 0x9d, 0x44, 0x35, 0x59, 0x2f, 0x6c, 0xdf, 0x6d, 0x84, 0xd1, 0x85,
-	0xfe, 0x1a, 0x3d, 0x6d, 0x15, 0x0a, 0xb8, 0x24, 0x63, 0x92, 0x44, 0xff, 0x05, 0x20, 0x13, 0xc5,
-	0x07, 0xfc, 0xf2, 0x92, 0x29, 0x27, 0x65, 0xf6, 0x89, 0x69, 0xd0, 0x3d, 0x28, 0x29, 0x22, 0x86,
-	0x54, 0xb9, 0x52, 0x09, 0xe6, 0x0f, 0x9d, 0x74, 0xd5, 0xaa, 0xe7, 0x71, 0x31, 0x50, 0xf6, 0x8c,
-	0x0e, 0x35, 0x21, 0xcb, 0xc7, 0xca, 0x84, 0x90, 0xa9, 0x5a, 0xf5, 0xc2, 0xf6, 0x66, 0x23, 0x38,
-	0x78, 0xfb, 0x47, 0x3a, 0x98, 0x28, 0xda, 0x0d, 0x40, 0x1c, 0x59, 0xa1, 0x5d, 0xb0, 0x63, 0xc7,
-	0x73, 0x2f, 0xb9, 0x47, 0x9d, 0x6c, 0xd5, 0xaa, 0x97, 0xb7, 0xff, 0x15, 0x05, 0x1f, 0x3b, 0xe9,
-	0x01, 0xf7, 0x28, 0xae, 0xa8, 0x65, 0x05, 0x6a, 0x42, 0xee, 0x8a, 0x08, 0x9f, 0xf9, 0x43, 0xe9,
-	0xe4, 0xcc, 0xc1, 0xd7, 0xc3, 0x5d, 0xbf, 0xd5, 0xbf, 0x27, 0x01, 0x86, 0xe7, 0x46, 0xe8, 0x2b,
-	0x28, 0x8e, 0x05, 0x5d, 0x64, 0x2b, 0x7f, 0x83, 0x6c, 0x15, 0xc6, 0x82, 0xce, 0x73, 0xb5, 0x03,
-	0xa5, 0x31, 0x97, 0x6a, 0xe1, 0x01, 0x6e, 0xe0, 0xa1, 0xa8, 0x97, 0xcc, 0x5d, 0xdc, 0x87, 0xf2,
-	0x88, 0x48, 0xe5, 0x32, 0x5f, 0x52, 0xa1, 0x5c, 0xe6, 0x39, 0x85, 0xaa, 0x55, 0x4f, 0xe1, 0xa2,
-	0xd6, 0x76, 0x8c, 0xb2, 0xe3, 0xa1, 0xff, 0x00, 0x9c, 0xf1, 0x89, 0xef, 0xb9, 0x82, 0x5f, 0x49,
-	0xa7, 0x68, 0x2c, 0xf2, 0x46, 0x83, 0xf9, 0x95, 0x44, 0x2e, 0xdc, 0x9e, 0x48, 0x2a, 0x5c, 0x8f,
-	0x9e, 0x31, 0x9f, 0x7a, 0xee, 0x94, 0x08, 0x46, 0x4e, 0x47, 0x54, 0x3a, 0x25, 0x13, 0xd0, 0xa3,
-	0xd5, 0x80, 0x8e, 0x24, 0x15, 0x7b, 0x81, 0xf1, 0x71, 0x64, 0xdb, 0xf6, 0x95, 0x98, 0xe1, 0x8d,
-	0xc9, 0x35, 0x10, 0xea, 0x82, 0x2d, 0x67, 0x52, 0xd1, 0xcb, 0x98, 0xeb, 0xb2, 0x71, 0x7d, 0xff,
-	0x95, 0xb3, 0x1a, 0xbb, 0x15, 0xaf, 0x15, 0xb9, 0xac, 0x45, 0xff, 0x86, 0xbc, 0xe0, 0x57, 0xee,
-	0x80, 0x4f, 0x7c, 0xe5, 0x54, 0xaa, 0x56, 0x3d, 0x89, 0x73, 0x82, 0x5f, 0xb5, 0xb4, 0xac, 0x4b,
-	0x50, 0x92, 0x29, 0x1d, 0x73, 0xe6, 0x2b, 0xe9, 0xd8, 0xd5, 0x64, 0x3d, 0x8f, 0x63, 0x1a, 0x54,
-	0x07, 0x9b, 0xf9, 0xae, 0xa0, 0x92, 0x8a, 0x29, 0xf5, 0xdc, 0x01, 0xf7, 0x7d, 0x67, 0xcd, 0x14,
-	0x6a, 0x99, 0xf9, 0x38, 0x54, 0xb7, 0xb8, 0xef, 0x6b, 0x86, 0x47, 0x7c, 0x70, 0x11, 0x11, 0xe4,
-	0x20, 0x53, 0x8c, 0xaf, 0x61, 0x58, 0xaf, 0x88, 0x3a, 0xaf, 0x01, 0xeb, 0x86, 0x1e, 0xe3, 0xe5,
-	0x9c, 0x12, 0xa1, 0x4e, 0x29, 0x51, 0xce, 0xba, 0x89, 0x78, 0x4d, 0x43, 0xfb, 0x7c, 0x70, 0xf1,
-	0x4d, 0x04, 0xa0, 0xaf, 0xc1, 0x16, 0x94, 0x78, 0x2e, 0x39, 0x53, 0x54, 0xb8, 0x57, 0x82, 0x29,
-	0xea, 0x6c, 0x98, 0x4d, 0x6f, 0x47, 0x9b, 0x62, 0x4a, 0xbc, 0x1d, 0x0d, 0x9f, 0x68, 0x14, 0x97,
-	0xc5, 0x92, 0x8c, 0xaa, 0x50, 0xd8, 0xdb, 0xdb, 0xef, 0x29, 0x41, 0x14, 0x1d, 0xce, 0x9c, 0x4d,
-	0xd3, 0x5d, 0x71, 0xd5, 0xd6, 0x6f, 0x16, 0x14, 0xe3, 0x11, 0xa3, 0x07, 0x90, 0x09, 0xba, 0xcf,
-	0x5c, 0x0b, 0x85, 0xed, 0x52, 0x58, 0xf6, 0x7d, 0xa3, 0xc4, 0x21, 0xa8, 0x6f, 0x91, 0x78, 0x8f,
-	0x31, 0xcf, 0x49, 0x98, 0x63, 0x94, 0x62, 0xda, 0x8e, 0x87, 0x9e, 0x40, 0x51, 0x69, 0x92, 0x94,
-	0x4b, 0x46, 0x8c, 0x48, 0x27, 0x19, 0x36, 0xf0, 0xfc, 0xb2, 0xea, 0x1b, 0x74, 0x47, 0x83, 0xb8,
-	0xa0, 0x16, 0x02, 0xfa, 0x1f, 0x14, 0xe6, 0xa4, 0x30, 0xcf, 0xdc, 0x1d, 0x49, 0x0c, 0x91, 0xaa,
-	0xe3, 0x6d, 0x7d, 0x0f, 0x77, 0xfe, 0xb6, 0xf2, 0x90, 0x0d, 0xc9, 0x0b, 0x3a, 0x33, 0x47, 0xc8,
-	0x63, 0xfd, 0x89, 0x1e, 0x41, 0x7a, 0x4a, 0x46, 0x13, 0x6a, 0xe2, 0x5c, 0x74, 0xf3, 0x2e, 0xf3,
-	0xe7, 0x6b, 0x71, 0x60, 0xf1, 0x79, 0xe2, 0x89, 0xb5, 0xb5, 0x0b, 0x1b, 0xd7, 0x15, 0xdf, 0x35,
-	0x8e, 0x37, 0xe2, 0x8e, 0xf3, 0x31, 0x1f, 0xcf, 0x53, 0xb9, 0xa4, 0x9d, 0xaa, 0xfd, 0x6a, 0x41,
-	0x79, 0x99, 0x26, 0xf4, 0x11, 0x6c, 0xae, 0x12, 0xeb, 0x0e, 0x15, 0xf3, 0x42, 0xb7, 0x68, 0x99,
-	0xc5, 0x67, 0x8a, 0x79, 0xe8, 0x33, 0x70, 0x5e, 0x59, 0xa2, 0xd8, 0x25, 0xe5, 0x13, 0x65, 0x36,
-	0xb6, 0xf0, 0xe6, 0xf2, 0xaa, 0x7e, 0x00, 0xea, 0xa2, 0x0b, 0x0b, 0x56, 0xdf, 0xf9, 0x83, 0x0b,
-	0xb3, 0x51, 0x40, 0x44, 0x0e, 0xaf, 0x85, 0x50, 0x5f, 0x23, 0x7a, 0x1f, 0x59, 0xfb, 0x25, 0x01,
-	0xe5, 0xf0, 0x62, 0xc5, 0xf4, 0xe5, 0x84, 0x4a, 0x85, 0x3e, 0x80, 0xfc, 0x80, 0x8c, 0x46, 0x54,
-	0xb8, 0x61, 0x88, 0x85, 0xed, 0x4a, 0x23, 0x18, 0x2f, 0x2d, 0xa3, 0xef, 0xec, 0xe1, 0x5c, 0x60,
-	0xd1, 0xf1, 0xd0, 0x23, 0xc8, 0x46, 0x1d, 0x92, 0x98, 0xdb, 0xc6, 0x3b, 0x04, 0x47, 0x38, 0x7a,
-	0x08, 0x69, 0xc3, 0x42, 0x58, 0x16, 0x6b, 0x11, 0x27, 0xfa, 0x2e, 0x32, 0xd7, 0x2c, 0x0e, 0x70,
-	0xf4, 0x09, 0x84, 0xb5, 0xe1, 0xaa, 0xd9, 0x98, 0x9a, 0x62, 0x28, 0x6f, 0x6f, 0xac, 0x56, 0x51,
-	0x7f, 0x36, 0xa6, 0x18, 0xd4, 0xfc, 0x5b, 0x17, 0xe9, 0x05, 0x9d, 0xc9, 0x31, 0x19, 0x50, 0xd7,
-	0x0c, 0x26, 0x33, 0x40, 0xf2, 0xb8, 0x14, 0x69, 0x4d, 0xe5, 0xc7, 0x07, 0x4c, 0xf6, 0x26, 0x03,
-	0xe6, 0x79, 0x2a, 0x97, 0xb6, 0x33, 0xb5, 0x9f, 0x2c, 0xa8, 0xcc, 0x33, 0x25, 0xc7, 0xdc, 0x97,
-	0x7a, 0xc7, 0x34, 0x15, 0x82, 0x8b, 0x95, 0x34, 0xe1, 0xc3, 0x56, 0x5b, 0xab, 0x71, 0x80, 0xbe,
-	0x49, 0x8e, 0x1e, 0x43, 0x46, 0x50, 0x39, 0x19, 0xa9, 0x30, 0x49, 0x28, 0x3e, 0x86, 0xb0, 0x41,
-	0x70, 0x68, 0x51, 0xfb, 0x23, 0x01, 0xeb, 0x61, 0x44, 0xbb, 0x44, 0x0d, 0xce, 0xdf, 0x3b, 0x81,
-	0xff, 0x87, 0xac, 0x8e, 0x86, 0x51, 0x5d, 0x50, 0xc9, 0xeb, 0x29, 0x8c, 0x2c, 0xde, 0x81, 0x44,
-	0x22, 0x97, 0xde, 0x2b, 0xe9, 0xe0, 0xbd, 0x42, 0x64, 0xfc, 0xbd, 0xf2, 0x9e, 0xb8, 0xae, 0xfd,
-	0x6c, 0xc1, 0xc6, 0x72, 0x4e, 0xdf, 0x1b, 0xd5, 0x1f, 0x42, 0x36, 0x20, 0x32, 0xca, 0xe6, 0xed,
-	0x30, 0xb6, 0x80, 0xe6, 0x13, 0xa6, 0xce, 0x03, 0xd7, 0x91, 0x99, 0x6e, 0xd6, 0x8d, 0x9e, 0x12,
-	0x94, 0x5c, 0xbe, 0x53, 0xcb, 0xce, 0xfb, 0x30, 0xf1, 0x66, 0x7d, 0x98, 0x7c, 0xeb, 0x3e, 0x4c,
-	0xbd, 0x86, 0x9b, 0xf4, 0x8d, 0x1e, 0x7a, 0xb1, 0xdc, 0x66, 0xfe, 0x39, 0xb7, 0xb5, 0x16, 0x6c,
-	0xae, 0x24, 0x2a, 0xa4, 0x71, 0xd1, 0x5f, 0xd6, 0x6b, 0xfb, 0xeb, 0x07, 0xb8, 0x83, 0xa9, 0xe4,
-	0xa3, 0x29, 0x8d, 0x55, 0xde, 0xdb, 0xa5, 0x1c, 0x41, 0xca, 0x53, 0xe1, 0xd4, 0xcc, 0x63, 0xf3,
-	0x5d, 0xbb, 0x0b, 0x5b, 0xd7, 0xb9, 0x0f, 0x02, 0xad, 0xfd, 0x6e, 0x41, 0xf9, 0x38, 0x38, 0xc3,
-	0xdb, 0x6d, 0xb9, 0x42, 0x5e, 0xe2, 0x86, 0xe4, 0x3d, 0x84, 0xf4, 0xd4, 0x0c, 0xa7, 0xe8, 0x92,
-	0x8e, 0xfd, 0x0f, 0x39, 0xd6, 0x33, 0x03, 0x07, 0xb8, 0xce, 0xe4, 0x19, 0x1b, 0x29, 0x2a, 0x0c,
-	0xbb, 0x3a, 0x93, 0x31, 0xcb, 0xa7, 0x06, 0xc1, 0xa1, 0x45, 0xed, 0x4b, 0xa8, 0xcc, 0xcf, 0xb2,
-	0x20, 0x82, 0x4e, 0xa9, 0x7e, 0xa4, 0x59, 0xa6, 0xf8, 0x97, 0x96, 0x1f, 0xb7, 0x35, 0x84, 0x43,
-	0x8b, 0xc7, 0x7b, 0x50, 0x59, 0x79, 0xc1, 0xa3, 0x0a, 0x14, 0x8e, 0x5e, 0xf4, 0x0e, 0xdb, 0xad,
-	0xce, 0xd3, 0x4e, 0x7b, 0xcf, 0xbe, 0x85, 0x00, 0x32, 0xbd, 0xce, 0x8b, 0x67, 0xfb, 0x6d, 0xdb,
-	0x42, 0x79, 0x48, 0x1f, 0x1c, 0xed, 0xf7, 0x3b, 0x76, 0x42, 0x7f, 0xf6, 0x4f, 0xba, 0x87, 0x2d,
-	0x3b, 0xf9, 0xf8, 0x0b, 0x28, 0xb4, 0xcc, 0xff, 0x90, 0xae, 0xf0, 0xa8, 0xd0, 0x0b, 0x5e, 0x74,
-	0xf1, 0xc1, 0xce, 0xbe, 0x7d, 0x0b, 0x65, 0x21, 0x79, 0x88, 0xf5, 0xca, 0x1c, 0xa4, 0x0e, 0xbb,
-	0xbd, 0xbe, 0x9d, 0x40, 0x65, 0x80, 0x9d, 0xa3, 0x7e, 0xb7, 0xd5, 0x3d, 0x38, 0xe8, 0xf4, 0xed,
-	0xe4, 0xee, 0xa7, 0x50, 0x61, 0xbc, 0x31, 0x65, 0x8a, 0x4a, 0x19, 0xfc, 0xcd, 0xfa, 0xee, 0x5e,
-	0x28, 0x31, 0xde, 0x0c, 0xbe, 0x9a, 0x43, 0xde, 0x9c, 0xaa, 0xa6, 0x41, 0x9b, 0x41, 0x69, 0x9e,
-	0x66, 0x8c, 0xf4, 0xf1, 0x5f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x70, 0xa7, 0x77, 0x23, 0xe6, 0x0d,
-	0x00, 0x00,
+	// 1418 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb5, 0x57, 0x5f, 0x73, 0x1b, 0x35,
+	0x10, 0xc7, 0xff, 0xed, 0xf5, 0xdf, 0x28, 0x4e, 0x7a, 0x0d, 0x05, 0x8a, 0x5b, 0x86, 0xb6, 0x30,
+	0x0e, 0x84, 0x01, 0x3a, 0x0c, 0x4c, 0x71, 0x1c, 0xb7, 0x98, 0x49, 0xea, 0xa0, 0x38, 0xc9, 0x0c,
+	0x03, 0x73, 0xa3, 0xd8, 0x8a, 0x73, 0x53, 0xe7, 0xce, 0x9c, 0x64, 0xb7, 0xfe, 0x14, 0xbc, 0xf3,
+	0x05, 0x78, 0xe1, 0x9d, 0xef, 0x00, 0x4f, 0x7c, 0x23, 0x56, 0xd2, 0x9d, 0x7d, 0x76, 0x02, 0x49,
+	0xdb, 0xe9, 0x4b, 0x72, 0xda, 0xdf, 0x6a, 0xb5, 0xda, 0xdd, 0xdf, 0xae, 0x0c, 0x85, 0x89, 0x1c,
+	0x30, 0xc9, 0xeb, 0x23, 0xdf, 0x93, 0x1e, 0x49, 0x9b, 0xd5, 0x46, 0xe5, 0xc4, 0x71, 0x87, 0xde,
+	0xa0, 0xcf, 0x24, 0x33, 0xc8, 0x46, 0xfe, 0x97, 0x31, 0xf7, 0xa7, 0xc1, 0xa2, 0x24, 0xbd, 0x91,
+	0x17, 0x05, 0x27, 0xd2, 0x1f, 0xf5, 0xcc, 0xa2, 0xf6, 0x77, 0x11, 0x32, 0x07, 0x5c, 0x08, 0xc7,
+	0x73, 0xc9, 0x07, 0x50, 0x72, 0x5c, 0x5b, 0xfa, 0xcc, 0x15, 0xac, 0x27, 0x51, 0x62, 0xc5, 0x6e,
+	0xc7, 0xee, 0x65, 0x69, 0xd1, 0x71, 0xbb, 0x73, 0x21, 0x69, 0x42, 0x49, 0x9c, 0x31, 0xbf, 0x6f,
+	0x0b, 0xb3, 0x4f, 0x58, 0xf1, 0xdb, 0x89, 0x7b, 0xf9, 0xad, 0x5b, 0xf5, 0xc0, 0xbb, 0xc0, 0x5e,
+	0xfd, 0x40, 0x69, 0x05, 0x0b, 0x5a, 0x14, 0x91, 0x95, 0x20, 0xef, 0x02, 0xb0, 0xb1, 0xf4, 0x7a,
+	0xde, 0xf9, 0xb9, 0x23, 0xad, 0xa4, 0x3e, 0x27, 0x22, 0x21, 0x77, 0xa0, 0x28, 0x99, 0x3f, 0xe0,
+	0xd2, 0x16, 0xd2, 0x77, 0xdc, 0x81, 0x95, 0x42, 0x95, 0x1c, 0x2d, 0x18, 0xe1, 0x81, 0x96, 0x91,
+	0x4d, 0xc8, 0x78, 0x23, 0xa9, 0x5d, 0x48, 0x23, 0x9c, 0xdf, 0x5a, 0xab, 0x9b, 0x8b, 0xb7, 0x5e,
+	0xf0, 0xde, 0x58, 0xf2, 0x8e, 0x01, 0x69, 0xa8, 0x45, 0xb6, 0xa1, 0x12, 0xb9, 0x9e, 0x7d, 0xee,
+	0xf5, 0xb9, 0x95, 0xc1, 0x9d, 0xa5, 0xad, 0x1b, 0xa1, 0xf3, 0x91, 0x9b, 0xee, 0x21, 0x4c, 0xcb,
+	0x72, 0x51, 0x80, 0x87, 0x66, 0x9f, 0x33, 0xdf, 0xc5, 0xf3, 0x85, 0x95, 0xd5, 0x17, 0x5f, 0x0d,
+	0x4e, 0xfd, 0x41, 0xfd, 0x3d, 0x36, 0x18, 0x9d, 0x29, 0x91, 0x47, 0x50, 0x18, 0xf9, 0x7c, 0x1e,
+	0xad, 0xdc, 0x35, 0xa2, 0x95, 0xc7, 0x1d, 0xb3, 0x58, 0x35, 0xa0, 0x38, 0xf2, 0x84, 0x9c, 0x5b,
+	0x80, 0x6b, 0x58, 0x28, 0xa8, 0x2d, 0x33, 0x13, 0x77, 0xa1, 0x34, 0x64, 0x68, 0xc2, 0x71, 0x05,
+	0xf7, 0xf1, 0x5f, 0xdf, 0xca, 0xe3, 0xb5, 0x93, 0xb4, 0xa0, 0xa4, 0x6d, 0x2d, 0x6c, 0xf7, 0xc9,
+	0x3b, 0x00, 0xa7, 0xde, 0xd8, 0xed, 0xdb, 0xbe, 0xf7, 0x5c, 0x58, 0x05, 0xad, 0x91, 0xd3, 0x12,
+	0x8a, 0x02, 0x62, 0xc3, 0xfa, 0x18, 0x35, 0xed, 0x3e, 0x3f, 0x75, 0x5c, 0xde, 0xb7, 0x27, 0xcc,
+	0x77, 0xd8, 0xc9, 0x90, 0x0b, 0xab, 0xa8, 0x1d, 0xba, 0xbf, 0xec, 0xd0, 0x21, 0x6a, 0xef, 0x18,
+	0xe5, 0xa3, 0x50, 0xb7, 0xe5, 0x4a, 0x7f, 0x4a, 0xab, 0xe3, 0x4b, 0x20, 0xd2, 0x81, 0x8a, 0x98,
+	0x0a, 0xc9, 0xcf, 0x23, 0xa6, 0x4b, 0xda, 0xf4, 0xdd, 0x0b, 0x77, 0xd5, 0x7a, 0x4b, 0x56, 0xcb,
+	0x62, 0x51, 0x4a, 0xde, 0x86, 0x1c, 0x5e, 0xc5, 0xee, 0xe1, 0x15, 0xa4, 0x55, 0xc6, 0xfb, 0x24,
+	0x68, 0x16, 0x05, 0x4d, 0xb5, 0x56, 0x25, 0x28, 0xd8, 0x84, 0x8f, 0x3c, 0xc7, 0x95, 0xc2, 0xaa,
+	0xe0, 0x39, 0x39, 0x1a, 0x91, 0x90, 0x7b, 0x50, 0x41, 0x3a, 0xf8, 0x1c, 0x3d, 0x9d, 0xe0, 0x6d,
+	0x7b, 0x9e, 0xeb, 0x5a, 0x2b, 0xba, 0x50, 0x91, 0x26, 0x34, 0x10, 0x37, 0x51, 0xaa, 0x32, 0x3c,
+	0xf4, 0x7a, 0xcf, 0xc2, 0x04, 0x59, 0x44, 0x17, 0xe3, 0x15, 0x19, 0x56, 0x3b, 0x42, 0xe6, 0xd5,
+	0x61, 0x55, 0xa7, 0x47, 0x5b, 0x39, 0xe3, 0xcc, 0x97, 0x27, 0x9c, 0x49, 0x6b, 0x55, 0x7b, 0xbc,
+	0xa2, 0xa0, 0x5d, 0x44, 0xbe, 0x0b, 0x01, 0xf2, 0x2d, 0x54, 0x7c, 0xce, 0xfa, 0x36, 0x3b, 0x95,
+	0x98, 0x8f, 0xe7, 0xbe, 0x23, 0xb9, 0x55, 0xd5, 0x87, 0xae, 0x87, 0x87, 0x52, 0xc4, 0x1b, 0x0a,
+	0x3e, 0x56, 0x28, 0x2d, 0xf9, 0x0b, 0x6b, 0x72, 0x1b, 0xf2, 0x3b, 0x3b, 0xbb, 0xc8, 0x23, 0xd4,
+	0x1d, 0x4c, 0xad, 0x35, 0xcd, 0xae, 0xa8, 0x88, 0x3c, 0x86, 0x22, 0xeb, 0x4f, 0x1c, 0xe1, 0xf9,
+	0x53, 0xed, 0x97, 0xb5, 0xae, 0x33, 0xf1, 0xfe, 0xf2, 0xad, 0x1a, 0x81, 0x92, 0xf2, 0xd0, 0xa4,
+	0xa1, 0xc0, 0x22, 0x22, 0xf2, 0x39, 0xdc, 0xf0, 0xf9, 0x90, 0x33, 0xc1, 0xb5, 0x19, 0x61, 0x23,
+	0xf1, 0x02, 0xda, 0xdf, 0xd0, 0xd1, 0xac, 0x06, 0xb0, 0xd2, 0x16, 0x1d, 0xb7, 0x69, 0x1a, 0xc0,
+	0x1e, 0x52, 0xf5, 0x85, 0xbd, 0xe8, 0x81, 0xa5, 0x3d, 0xb8, 0xb3, 0xec, 0x41, 0xf7, 0xc5, 0x45,
+	0x1f, 0x4a, 0x72, 0x41, 0xb8, 0xf1, 0x67, 0x0c, 0x0a, 0xd1, 0xf8, 0x63, 0xb3, 0x4b, 0x9b, 0x5e,
+	0xa2, 0x9b, 0x5c, 0x7e, 0xab, 0x18, 0x90, 0xb8, 0xab, 0x85, 0x34, 0x00, 0x55, 0x4f, 0x8c, 0x76,
+	0x0c, 0x24, 0x4e, 0x5c, 0x27, 0xa5, 0x18, 0x91, 0x22, 0x73, 0x1e, 0x02, 0x76, 0x26, 0x2c, 0x39,
+	0x69, 0xb3, 0xa1, 0xc3, 0x84, 0x95, 0x08, 0xda, 0xd1, 0xac, 0xf5, 0x76, 0x35, 0xda, 0x50, 0x20,
+	0xcd, 0xcb, 0xf9, 0x82, 0xbc, 0x07, 0xf9, 0x59, 0x89, 0xa1, 0xf5, 0xa4, 0xb6, 0x0e, 0xa1, 0xa8,
+	0xdd, 0xdf, 0xf8, 0x09, 0x6e, 0xfe, 0x27, 0x8f, 0x48, 0x05, 0x12, 0xcf, 0xf8, 0x54, 0x5f, 0x21,
+	0x47, 0xd5, 0x27, 0xb9, 0x0f, 0xa9, 0x09, 0x1b, 0x8e, 0xb9, 0xf6, 0x73, 0xde, 0x9b, 0xb6, 0x1d,
+	0x77, 0xb6, 0x97, 0x1a, 0x8d, 0xaf, 0xe2, 0x0f, 0x63, 0x1b, 0xdb, 0x50, 0xbd, 0x8c, 0x4a, 0x97,
+	0x18, 0xae, 0x46, 0x0d, 0xe7, 0xa2, 0x36, 0x1e, 0xc1, 0xca, 0x85, 0x04, 0x5c, 0x65, 0x20, 0x11,
+	0x35, 0xd0, 0x80, 0xd5, 0x4b, 0x72, 0xf8, 0x32, 0x26, 0xbe, 0x4f, 0x66, 0x13, 0x95, 0x64, 0xed,
+	0x8f, 0x18, 0x94, 0x16, 0x0b, 0x9f, 0x7c, 0x0a, 0x6b, 0xcb, 0x54, 0xb1, 0x07, 0x12, 0x23, 0x6d,
+	0xcc, 0x92, 0x45, 0x5e, 0x3c, 0x41, 0x84, 0x7c, 0x09, 0xd6, 0x85, 0x2d, 0xd2, 0x39, 0xe7, 0xde,
+	0x58, 0xea, 0x83, 0x63, 0x74, 0x6d, 0x71, 0x57, 0xd7, 0x80, 0x8a, 0xc6, 0x41, 0x0b, 0x50, 0x53,
+	0x14, 0xa9, 0xac, 0x0e, 0x32, 0xc5, 0x90, 0xa5, 0x2b, 0x01, 0xd4, 0x55, 0x88, 0x3a, 0x47, 0xd4,
+	0x7e, 0x8f, 0x43, 0x29, 0x18, 0x55, 0x94, 0x63, 0x9e, 0x84, 0x24, 0x1f, 0x43, 0xae, 0xc7, 0x86,
+	0x43, 0x3c, 0x37, 0x70, 0x31, 0xbf, 0x55, 0xae, 0x9b, 0x81, 0xdd, 0xd4, 0xf2, 0xf6, 0x0e, 0xcd,
+	0x1a, 0x0d, 0x2c, 0xbb, 0xfb, 0x90, 0x09, 0x7b, 0x4e, 0x7c, 0xa6, 0x1b, 0xe5, 0x06, 0x0d, 0x71,
+	0xf2, 0x21, 0xa4, 0x74, 0x25, 0x04, 0xa5, 0xb9, 0x12, 0xd6, 0x85, 0xea, 0xee, 0x7a, 0x70, 0x51,
+	0x83, 0x23, 0x5f, 0x83, 0xfa, 0xb4, 0xe5, 0x74, 0xc4, 0x75, 0x41, 0x96, 0xb6, 0xaa, 0xcb, 0x95,
+	0xdc, 0x45, 0x8c, 0x82, 0x9c, 0x7d, 0x2b, 0xa2, 0x60, 0x86, 0xc4, 0x88, 0xf5, 0x70, 0xd4, 0x29,
+	0xa2, 0xe9, 0x91, 0x9c, 0xa3, 0xc5, 0x50, 0xaa, 0xd9, 0x17, 0x1d, 0xd9, 0x99, 0xeb, 0x8c, 0x6c,
+	0x4c, 0x6c, 0xaa, 0x92, 0xae, 0xfd, 0x1a, 0x83, 0xf2, 0x2c, 0x52, 0x62, 0x84, 0x80, 0x3a, 0x31,
+	0xc5, 0x7d, 0xdf, 0xf3, 0x97, 0xc2, 0x44, 0xf7, 0x9b, 0x2d, 0x25, 0xa6, 0x06, 0x7d, 0x99, 0x18,
+	0x3d, 0x80, 0x34, 0x12, 0x6f, 0x3c, 0x94, 0x41, 0x90, 0x48, 0x74, 0xb0, 0x53, 0x8d, 0xd0, 0x40,
+	0xa3, 0xf6, 0x4f, 0x1c, 0x56, 0x03, 0x8f, 0xb6, 0x99, 0xec, 0x9d, 0xbd, 0xf1, 0x04, 0x7e, 0x04,
+	0x19, 0xe5, 0x8d, 0xc3, 0x55, 0x41, 0x25, 0x2e, 0x4f, 0x61, 0xa8, 0xf1, 0x1a, 0x49, 0x64, 0x62,
+	0xe1, 0x05, 0x98, 0x32, 0x2f, 0x40, 0x26, 0xa2, 0x2f, 0xc0, 0x37, 0x94, 0xeb, 0xda, 0x6f, 0x31,
+	0xa8, 0x2e, 0xc6, 0xf4, 0x8d, 0xa5, 0xfa, 0x13, 0xc8, 0x98, 0x44, 0x86, 0xd1, 0x5c, 0x0f, 0x7c,
+	0x33, 0x69, 0x3e, 0x76, 0xe4, 0x99, 0x31, 0x1d, 0xaa, 0x29, 0xb2, 0x56, 0x71, 0x38, 0x72, 0x76,
+	0xfe, 0x5a, 0x94, 0x9d, 0xf1, 0x30, 0xfe, 0x72, 0x3c, 0x4c, 0xbc, 0x32, 0x0f, 0x93, 0x57, 0xe4,
+	0x26, 0x75, 0xad, 0xa7, 0x73, 0x24, 0xb6, 0xe9, 0xff, 0x8f, 0x6d, 0xad, 0x09, 0x6b, 0x4b, 0x81,
+	0x0a, 0xd2, 0x38, 0xe7, 0x57, 0xec, 0x4a, 0x7e, 0xfd, 0x0c, 0x37, 0x51, 0xe2, 0x0d, 0x27, 0x3c,
+	0x52, 0x79, 0xaf, 0x16, 0x72, 0x02, 0xc9, 0xbe, 0x0c, 0x26, 0x77, 0x8e, 0xea, 0xef, 0xda, 0x2d,
+	0xd8, 0xb8, 0xcc, 0xbc, 0x71, 0xb4, 0xf6, 0x17, 0xce, 0x91, 0x23, 0x73, 0x87, 0x57, 0x3b, 0x72,
+	0x29, 0x79, 0xf1, 0x6b, 0x26, 0x0f, 0x8b, 0x63, 0xa2, 0x87, 0x53, 0xd8, 0xa4, 0x23, 0xbf, 0xec,
+	0x8e, 0xd4, 0xcc, 0xa0, 0x06, 0x57, 0x91, 0x3c, 0x75, 0x86, 0x38, 0x7f, 0x74, 0x76, 0x55, 0x24,
+	0x23, 0x9a, 0x8f, 0x35, 0x42, 0x03, 0x8d, 0xda, 0x37, 0x50, 0x9e, 0xdd, 0x65, 0x9e, 0x08, 0x3e,
+	0xe1, 0xea, 0xd9, 0x1b, 0xd3, 0xc5, 0xbf, 0xb0, 0xfd, 0xa8, 0xa5, 0x20, 0x1a, 0x68, 0x3c, 0xd8,
+	0x81, 0xf2, 0xd2, 0x6f, 0x22, 0x52, 0x86, 0xfc, 0xe1, 0xd3, 0x83, 0xfd, 0x56, 0xb3, 0xfd, 0xb8,
+	0xdd, 0xda, 0xa9, 0xbc, 0x45, 0x00, 0xd2, 0x07, 0xed, 0xa7, 0x4f, 0x76, 0x5b, 0x95, 0x18, 0xc9,
+	0x41, 0x6a, 0xef, 0x70, 0xb7, 0xdb, 0xae, 0xc4, 0xd5, 0x67, 0xf7, 0xb8, 0xb3, 0xdf, 0xac, 0x24,
+	0x1e, 0x7c, 0x0d, 0x79, 0xf3, 0xb0, 0xeb, 0xf8, 0x7d, 0xee, 0xab, 0x0d, 0x4f, 0x3b, 0x74, 0xaf,
+	0xb1, 0x8b, 0x9b, 0x33, 0x90, 0xd8, 0xa7, 0x6a, 0x67, 0x16, 0x92, 0xfb, 0x9d, 0x83, 0x2e, 0x6e,
+	0x2c, 0x01, 0x34, 0x0e, 0xbb, 0x9d, 0x66, 0x67, 0x6f, 0xaf, 0xdd, 0xad, 0x24, 0xb6, 0xbf, 0x80,
+	0xb2, 0xe3, 0xd5, 0x27, 0x38, 0x6a, 0x85, 0x30, 0x3f, 0x5c, 0x7f, 0xbc, 0x13, 0xac, 0x1c, 0x6f,
+	0xd3, 0x7c, 0x6d, 0x0e, 0xf0, 0x4b, 0x6e, 0x6a, 0x74, 0xd3, 0x94, 0xe6, 0x49, 0x5a, 0xaf, 0x3e,
+	0xfb, 0x17, 0x42, 0x8b, 0x6f, 0x15, 0x38, 0x0f, 0x00, 0x00,
 }
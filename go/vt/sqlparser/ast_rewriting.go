@@ -236,7 +236,8 @@ func (er *expressionRewriter) sysVarRewrite(cursor *Cursor, node *ColName) {
 		sysvars.DDLStrategy.Name,
 		sysvars.ReadAfterWriteGTID.Name,
 		sysvars.ReadAfterWriteTimeOut.Name,
-		sysvars.SessionTrackGTIDs.Name:
+		sysvars.SessionTrackGTIDs.Name,
+		sysvars.ReleaseLocksOnCommit.Name:
 		cursor.Replace(bindVarExpression("__vt" + lowered))
 		er.bindVars.AddSysVar(lowered)
 	}
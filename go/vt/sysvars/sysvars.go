@@ -61,6 +61,9 @@ var (
 	ReadAfterWriteTimeOut = SystemVariable{Name: "read_after_write_timeout"}
 	SessionTrackGTIDs     = SystemVariable{Name: "session_track_gtids", IdentifierAsString: true}
 
+	// ReleaseLocksOnCommit releases the advisory locks acquired during a transaction on commit.
+	ReleaseLocksOnCommit = SystemVariable{Name: "release_locks_on_commit", IsBoolean: true, Default: off}
+
 	VitessAware = []SystemVariable{
 		Autocommit,
 		ClientFoundRows,
@@ -76,6 +79,7 @@ var (
 		ReadAfterWriteGTID,
 		ReadAfterWriteTimeOut,
 		SessionTrackGTIDs,
+		ReleaseLocksOnCommit,
 	}
 
 	IgnoreThese = []SystemVariable{
@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/srvtopo"
+)
+
+var _ Primitive = (*analyzeNode)(nil)
+
+// analyzeNode wraps a Primitive and records the number of rows it produced
+// and the time spent producing them. It is used to build the EXPLAIN ANALYZE output.
+type analyzeNode struct {
+	Primitive
+
+	// inputs are the instrumented inputs of the wrapped primitive.
+	inputs []Primitive
+
+	rows    uint64
+	elapsed int64
+}
+
+// ExecuteAnalyze executes the primitive tree in analyze mode. Along with the result,
+// it returns the plan description of the tree where every node carries the actual
+// number of rows it produced and the time it took to execute.
+func ExecuteAnalyze(vcursor VCursor, p Primitive, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, PrimitiveDescription, error) {
+	root := analyzePrimitive(p)
+	qr, err := root.Execute(vcursor, bindVars, wantfields)
+	if err != nil {
+		return nil, PrimitiveDescription{}, err
+	}
+	return qr, PrimitiveToPlanDescription(root), nil
+}
+
+// analyzable is implemented by the primitives with inputs, so that ExecuteAnalyze instruments
+// their inputs too. The primitives that do not implement it are measured as a whole.
+type analyzable interface {
+	// withInputs returns a shallow copy of the primitive where its inputs are replaced
+	// by the given ones, in the order of Inputs.
+	withInputs(inputs []Primitive) Primitive
+}
+
+// analyzePrimitive returns an instrumented copy of the primitive tree.
+// The original tree is left untouched, since plans are shared through the plan cache.
+func analyzePrimitive(p Primitive) Primitive {
+	if p == nil {
+		return nil
+	}
+	prim, inputs := p, p.Inputs()
+	if a, ok := p.(analyzable); ok && len(inputs) != 0 {
+		in := make([]Primitive, len(inputs))
+		for i, input := range inputs {
+			in[i] = analyzePrimitive(input)
+		}
+		prim, inputs = a.withInputs(in), in
+	}
+	node := &analyzeNode{Primitive: prim, inputs: inputs}
+	if _, ok := prim.(shardLock); ok {
+		return analyzeShardLockNode{node}
+	}
+	return node
+}
+
+// analyzeShardLockNode instruments a lock that its parent executes on a shard resolved
+// beforehand, like the ones of ConditionalLock.
+type analyzeShardLockNode struct {
+	*analyzeNode
+}
+
+var _ shardLock = analyzeShardLockNode{}
+
+func (n analyzeShardLockNode) resolveShard(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*srvtopo.ResolvedShard, error) {
+	return n.Primitive.(shardLock).resolveShard(vcursor, bindVars)
+}
+
+// ExecuteOnShards implements the shardLock interface
+func (n analyzeShardLockNode) ExecuteOnShards(vcursor VCursor, rss []*srvtopo.ResolvedShard, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	start := time.Now()
+	qr, err := n.Primitive.(shardLock).ExecuteOnShards(vcursor, rss, bindVars)
+	n.record(qr, start)
+	return qr, err
+}
+
+// Inputs implements the Primitive interface
+func (n *analyzeNode) Inputs() []Primitive {
+	return n.inputs
+}
+
+// Execute implements the Primitive interface
+func (n *analyzeNode) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	start := time.Now()
+	qr, err := n.Primitive.Execute(vcursor, bindVars, wantfields)
+	n.record(qr, start)
+	return qr, err
+}
+
+// StreamExecute implements the Primitive interface
+func (n *analyzeNode) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	start := time.Now()
+	err := n.Primitive.StreamExecute(vcursor, bindVars, wantfields, func(qr *sqltypes.Result) error {
+		atomic.AddUint64(&n.rows, uint64(len(qr.Rows)))
+		return callback(qr)
+	})
+	n.record(nil, start)
+	return err
+}
+
+func (n *analyzeNode) record(qr *sqltypes.Result, start time.Time) {
+	atomic.AddInt64(&n.elapsed, int64(time.Since(start)))
+	if qr != nil {
+		atomic.AddUint64(&n.rows, uint64(len(qr.Rows)))
+	}
+}
+
+// Cost implements the Coster interface
+func (n *analyzeNode) Cost() int {
+	if c, ok := n.Primitive.(Coster); ok {
+		return c.Cost()
+	}
+	return 0
+}
+
+func (n *analyzeNode) description() PrimitiveDescription {
+	d := n.Primitive.description()
+	d.ActualRows = atomic.LoadUint64(&n.rows)
+	d.ActualTime = time.Duration(atomic.LoadInt64(&n.elapsed))
+	return d
+}
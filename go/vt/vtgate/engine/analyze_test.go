@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestExecuteAnalyze(t *testing.T) {
+	fields := sqltypes.MakeTestFields("col", "int64")
+	input := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(fields, "1", "2", "3")},
+	}
+	l := &Limit{
+		Count: int64PlanValue(2),
+		Input: input,
+	}
+
+	result, desc, err := ExecuteAnalyze(&noopVCursor{}, l, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	assert.Len(t, result.Rows, 2)
+
+	assert.Equal(t, "Limit", desc.OperatorType)
+	assert.EqualValues(t, 2, desc.ActualRows)
+	assert.NotZero(t, desc.ActualTime)
+	require.Len(t, desc.Inputs, 1)
+	assert.Equal(t, "fake", desc.Inputs[0].OperatorType)
+	assert.EqualValues(t, 3, desc.Inputs[0].ActualRows)
+	assert.NotZero(t, desc.Inputs[0].ActualTime)
+
+	// the cached plan must not be modified by the instrumentation
+	assert.Same(t, input, l.Input)
+	assert.Zero(t, PrimitiveToPlanDescription(l).ActualRows)
+}
+
+func TestExecuteAnalyzeLock(t *testing.T) {
+	lock := &Lock{
+		Keyspace:          &vindexes.Keyspace{Name: "ks"},
+		TargetDestination: key.DestinationKeyspaceID{0},
+		Query:             "select get_lock('lock name', 10) from dual",
+	}
+	vc := &loggingVCursor{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1")},
+	}
+
+	_, desc, err := ExecuteAnalyze(vc, lock, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, "Lock", desc.OperatorType)
+	assert.EqualValues(t, 1, desc.ActualRows)
+	assert.NotZero(t, desc.ActualTime)
+}
+
+func TestExecuteAnalyzeInputs(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1", "2")
+
+	// the input of ForUpdate is instrumented.
+	route := NewRoute(SelectUnsharded, ks, "select id from t for update", "select id from t where 1 != 1")
+	f := &ForUpdate{Input: route}
+	vc := &loggingVCursor{shards: []string{"0"}, results: []*sqltypes.Result{result}, inTransaction: true}
+	_, desc, err := ExecuteAnalyze(vc, f, nil, false)
+	require.NoError(t, err)
+	require.Len(t, desc.Inputs, 1)
+	assert.Equal(t, "Route", desc.Inputs[0].OperatorType)
+	assert.EqualValues(t, 2, desc.Inputs[0].ActualRows)
+	assert.Same(t, route, f.Input)
+
+	// the locks of ConditionalLock are instrumented, though they are executed on a shard resolved beforehand.
+	predicate, err := NewLock(ks, key.DestinationKeyspaceID{0}, "select state = 'ready' from resource where id = 1")
+	require.NoError(t, err)
+	lock, err := NewLock(ks, key.DestinationKeyspaceID{0}, "select get_lock('analyzed lock', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("analyzed lock")}}))
+	require.NoError(t, err)
+	cl, err := NewConditionalLock(predicate, lock)
+	require.NoError(t, err)
+	vc = &loggingVCursor{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("state = 'ready'", "int64"), "1"),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('analyzed lock', 10)", "int64"), "1"),
+	}}
+	defer lockWaits.releasedAll(vc)
+	_, desc, err = ExecuteAnalyze(vc, cl, nil, false)
+	require.NoError(t, err)
+	require.Len(t, desc.Inputs, 2)
+	for _, input := range desc.Inputs {
+		assert.Equal(t, "Lock", input.OperatorType)
+		assert.EqualValues(t, 1, input.ActualRows)
+		assert.True(t, input.ReservedConn)
+	}
+	assert.Same(t, predicate, cl.Predicate)
+
+	// SQLCalcFoundRows is not a pointer: its copy gets the instrumented inputs.
+	limit, count := &fakePrimitive{}, &fakePrimitive{}
+	foundRows := SQLCalcFoundRows{LimitPrimitive: limit, CountPrimitive: count}
+	analyzed := analyzePrimitive(foundRows).(*analyzeNode)
+	cp := analyzed.Primitive.(SQLCalcFoundRows)
+	assert.IsType(t, &analyzeNode{}, cp.LimitPrimitive)
+	assert.Same(t, limit, cp.LimitPrimitive.(*analyzeNode).Primitive)
+	assert.Same(t, count, cp.CountPrimitive.(*analyzeNode).Primitive)
+	assert.Equal(t, []Primitive{cp.LimitPrimitive, cp.CountPrimitive}, analyzed.Inputs())
+	assert.Same(t, limit, foundRows.LimitPrimitive)
+
+	// the primitives that are not analyzable are measured as a whole.
+	status, err := NewLockStatus(lock)
+	require.NoError(t, err)
+	analyzed = analyzePrimitive(status).(*analyzeNode)
+	assert.Same(t, status, analyzed.Primitive)
+	assert.Equal(t, status.Inputs(), analyzed.Inputs())
+}
+
+func TestExplainAnalyze(t *testing.T) {
+	input := &fakePrimitive{
+		results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("col", "int64"), "1", "2", "3")},
+	}
+	e := &ExplainAnalyze{Input: input}
+
+	qr, err := e.Execute(&noopVCursor{}, nil, true)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+	assert.Equal(t, "EXPLAIN", qr.Fields[0].Name)
+	plan := qr.Rows[0][0].ToString()
+	assert.Contains(t, plan, `"OperatorType": "fake"`)
+	assert.Contains(t, plan, `"ActualRows": 3`)
+
+	fields, err := e.GetFields(&noopVCursor{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, qr.Fields, fields.Fields)
+}
@@ -36,8 +36,8 @@ type Concatenate struct {
 }
 
 //RouteType returns a description of the query routing type used by the primitive
-func (c *Concatenate) RouteType() string {
-	return "Concatenate"
+func (c *Concatenate) RouteType() RouteType {
+	return RouteTypeConcatenate
 }
 
 // GetKeyspaceName specifies the Keyspace that this primitive routes to
@@ -50,6 +50,17 @@ func (c *Concatenate) GetTableName() string {
 	return formatTwoOptionsNicely(c.Sources[0].GetTableName(), c.Sources[1].GetTableName())
 }
 
+// HasSideEffects implements the Primitive interface
+func (c *Concatenate) HasSideEffects() bool {
+	return inputsHaveSideEffects(c)
+}
+
+// EstimatedMemory implements the Primitive interface
+// It holds the results of all its sources.
+func (c *Concatenate) EstimatedMemory() int64 {
+	return primitiveMemory + bufferedRows*rowMemory + inputsMemory(c)
+}
+
 func formatTwoOptionsNicely(a, b string) string {
 	if a == b {
 		return a
@@ -203,8 +214,15 @@ func (c *Concatenate) Inputs() []Primitive {
 	return c.Sources
 }
 
+// withInputs implements the analyzable interface
+func (c *Concatenate) withInputs(inputs []Primitive) Primitive {
+	cp := *c
+	cp.Sources = inputs
+	return &cp
+}
+
 func (c *Concatenate) description() PrimitiveDescription {
-	return PrimitiveDescription{OperatorType: c.RouteType()}
+	return PrimitiveDescription{OperatorType: c.RouteType().String()}
 }
 
 func compareFields(fields1 []*querypb.Field, fields2 []*querypb.Field) error {
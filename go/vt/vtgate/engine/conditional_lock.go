@@ -0,0 +1,162 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"strconv"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*ConditionalLock)(nil)
+
+// ConditionalLock primitive acquires an advisory lock only if its predicate holds,
+// e.g. to lock a resource only while it is in a given state.
+// The predicate query is sent first, on the lock connection of the session on the shard
+// of the lock, so that it sees what the acquisition is conditioned on from the same
+// connection. Use NewConditionalLock to create one.
+type ConditionalLock struct {
+	// Predicate is the query deciding whether the lock is acquired. It must return a single
+	// row, whose first column is true, as MySQL tests a condition, to acquire the lock.
+	Predicate shardLock
+
+	// Lock acquires the lock.
+	Lock shardLock
+
+	noTxNeeded
+}
+
+// shardLock is a lock query its parent sends to a shard it resolved beforehand. It is implemented
+// by Lock, and by the instrumented locks of EXPLAIN ANALYZE.
+type shardLock interface {
+	Primitive
+	resolveShard(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*srvtopo.ResolvedShard, error)
+	ExecuteOnShards(vcursor VCursor, rss []*srvtopo.ResolvedShard, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error)
+}
+
+// NewConditionalLock creates a ConditionalLock primitive acquiring the lock if the predicate holds.
+// Both primitives must be in the same keyspace, and the predicate is sent to the shard of the lock.
+func NewConditionalLock(predicate, lock *Lock) (*ConditionalLock, error) {
+	if predicate == nil {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "conditional lock primitive requires a predicate")
+	}
+	if lock == nil {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "conditional lock primitive requires a lock")
+	}
+	if predicate.Keyspace.Name != lock.Keyspace.Name {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "conditional lock predicate keyspace %s differs from the lock keyspace %s", predicate.Keyspace.Name, lock.Keyspace.Name)
+	}
+	return &ConditionalLock{
+		Predicate: predicate,
+		Lock:      lock,
+	}, nil
+}
+
+// RouteType is part of the Primitive interface
+func (c *ConditionalLock) RouteType() RouteType {
+	return RouteTypeConditionalLock
+}
+
+// GetKeyspaceName is part of the Primitive interface
+func (c *ConditionalLock) GetKeyspaceName() string {
+	return c.Lock.GetKeyspaceName()
+}
+
+// GetTableName is part of the Primitive interface
+func (c *ConditionalLock) GetTableName() string {
+	return c.Lock.GetTableName()
+}
+
+// HasSideEffects is part of the Primitive interface.
+// It acquires the lock when the predicate holds.
+func (c *ConditionalLock) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory is part of the Primitive interface.
+func (c *ConditionalLock) EstimatedMemory() int64 {
+	return primitiveMemory + inputsMemory(c)
+}
+
+// Execute is part of the Primitive interface
+// If the predicate does not hold, the lock is not acquired and no row is returned.
+func (c *ConditionalLock) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	rs, err := c.Lock.resolveShard(vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	rss := []*srvtopo.ResolvedShard{rs}
+
+	qr, err := c.Predicate.ExecuteOnShards(vcursor, rss, bindVars)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "conditional lock predicate")
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) == 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "conditional lock predicate must return a single row, got: %d", len(qr.Rows))
+	}
+	if !truthy(qr.Rows[0][0]) {
+		if !wantfields {
+			return &sqltypes.Result{}, nil
+		}
+		return c.GetFields(vcursor, bindVars)
+	}
+	return c.Lock.ExecuteOnShards(vcursor, rss, bindVars)
+}
+
+// truthy returns true if MySQL would consider the value true in a condition.
+func truthy(v sqltypes.Value) bool {
+	if v.IsNull() {
+		return false
+	}
+	f, err := strconv.ParseFloat(v.ToString(), 64)
+	return err == nil && f != 0
+}
+
+// StreamExecute is part of the Primitive interface
+func (c *ConditionalLock) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	qr, err := c.Execute(vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// GetFields is part of the Primitive interface
+func (c *ConditionalLock) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return c.Lock.GetFields(vcursor, bindVars)
+}
+
+// Inputs is part of the Primitive interface
+func (c *ConditionalLock) Inputs() []Primitive {
+	return []Primitive{c.Predicate, c.Lock}
+}
+
+// withInputs implements the analyzable interface
+func (c *ConditionalLock) withInputs(inputs []Primitive) Primitive {
+	cp := *c
+	cp.Predicate = inputs[0].(shardLock)
+	cp.Lock = inputs[1].(shardLock)
+	return &cp
+}
+
+func (c *ConditionalLock) description() PrimitiveDescription {
+	return PrimitiveDescription{OperatorType: "ConditionalLock"}
+}
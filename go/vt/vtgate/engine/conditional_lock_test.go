@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestNewConditionalLock(t *testing.T) {
+	predicate, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select 1 from dual")
+	require.NoError(t, err)
+	lock, err := NewLock(&vindexes.Keyspace{Name: "other"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+
+	_, err = NewConditionalLock(nil, lock)
+	require.EqualError(t, err, "conditional lock primitive requires a predicate")
+	_, err = NewConditionalLock(predicate, nil)
+	require.EqualError(t, err, "conditional lock primitive requires a lock")
+	_, err = NewConditionalLock(predicate, lock)
+	require.EqualError(t, err, "conditional lock predicate keyspace ks differs from the lock keyspace other")
+}
+
+func TestConditionalLock(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	predicate, err := NewLock(ks, key.DestinationKeyspaceID{0}, "select state = 'ready' from resource where id = 1")
+	require.NoError(t, err)
+	lock, err := NewLock(ks, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}}))
+	require.NoError(t, err)
+	cl, err := NewConditionalLock(predicate, lock)
+	require.NoError(t, err)
+	assert.True(t, cl.HasSideEffects())
+
+	ready := sqltypes.MakeTestResult(sqltypes.MakeTestFields("state = 'ready'", "int64"), "1")
+	notReady := sqltypes.MakeTestResult(sqltypes.MakeTestFields("state = 'ready'", "int64"), "0")
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1")
+
+	// the predicate holds: the lock is acquired on the shard the predicate was sent to.
+	vc := &loggingVCursor{results: []*sqltypes.Result{ready, acquired}}
+	defer lockWaits.releasedAll(vc)
+	qr, err := cl.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, acquired.Rows, qr.Rows)
+	assert.EqualValues(t, 1, vc.AdvisoryLockCount("lock name"))
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select state = 'ready' from resource where id = 1 {}",
+		"ExecuteLock ks.-20: select get_lock('lock name', 10) from dual {}",
+		"SetAdvisoryLockCount lock name 1",
+	})
+
+	// the predicate does not hold: the lock is skipped.
+	vc = &loggingVCursor{results: []*sqltypes.Result{notReady}}
+	qr, err = cl.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.Empty(t, qr.Rows)
+	assert.Empty(t, vc.advisoryLocks)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select state = 'ready' from resource where id = 1 {}",
+	})
+
+	// a predicate that is not a single row is rejected.
+	vc = &loggingVCursor{results: []*sqltypes.Result{{}}}
+	_, err = cl.Execute(vc, nil, false)
+	require.EqualError(t, err, "conditional lock predicate must return a single row, got: 0")
+	assert.Empty(t, vc.advisoryLocks)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"vitess.io/vitess/go/vt/key"
+)
+
+const (
+	// singleShardCost is the cost of sending a query to a single shard.
+	singleShardCost = 1
+	// multiShardCost is the cost of sending a query to a destination that can map to many shards.
+	// The number of shards is not known at plan time, so this is a rough estimate.
+	multiShardCost = 10
+)
+
+// Coster is implemented by the primitives that can estimate the cost of executing them.
+// Primitives that do not implement it are assumed to be free. The cost does not include the one of the inputs.
+type Coster interface {
+	Cost() int
+}
+
+// destinationCost estimates the cost of sending a query to the destination.
+func destinationCost(dest key.Destination) int {
+	if dest == nil || dest.IsUnique() {
+		return singleShardCost
+	}
+	return multiShardCost
+}
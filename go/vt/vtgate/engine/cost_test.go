@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestCost(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks", Sharded: true}
+	query := "select get_lock('lock name', 10) from dual"
+	broadcast := &Send{
+		Keyspace:          ks,
+		TargetDestination: key.DestinationAllShards{},
+		Query:             query,
+	}
+	single := &Lock{
+		Keyspace:          ks,
+		TargetDestination: key.DestinationKeyspaceID{0},
+		Query:             query,
+	}
+	assert.Equal(t, multiShardCost, broadcast.Cost())
+	assert.Equal(t, singleShardCost, single.Cost())
+
+	// the cost of a primitive does not include the one of its inputs.
+	limit := &Limit{Count: int64PlanValue(1), Input: broadcast}
+	assert.Equal(t, multiShardCost, PrimitiveToPlanDescription(limit).Inputs[0].Cost)
+	assert.Zero(t, PrimitiveToPlanDescription(limit).Cost)
+}
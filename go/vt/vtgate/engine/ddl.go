@@ -53,8 +53,8 @@ func (v *DDL) description() PrimitiveDescription {
 }
 
 // RouteType implements the Primitive interface
-func (v *DDL) RouteType() string {
-	return "DDL"
+func (v *DDL) RouteType() RouteType {
+	return RouteTypeDDL
 }
 
 // GetKeyspaceName implements the Primitive interface
@@ -67,6 +67,16 @@ func (v *DDL) GetTableName() string {
 	return v.DDL.GetTable().Name.String()
 }
 
+// HasSideEffects implements the Primitive interface
+func (v *DDL) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory implements the Primitive interface
+func (v *DDL) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // IsOnlineSchemaDDL returns true if the query is an online schema change DDL
 func (v *DDL) isOnlineSchemaDDL() bool {
 	switch v.DDL.GetAction() {
@@ -50,9 +50,17 @@ var delName = map[DMLOpcode]string{
 	ByDestination: "DeleteByDestination",
 }
 
+var delRouteType = map[DMLOpcode]RouteType{
+	Unsharded:     RouteTypeDeleteUnsharded,
+	Equal:         RouteTypeDeleteEqual,
+	In:            RouteTypeDeleteIn,
+	Scatter:       RouteTypeDeleteScatter,
+	ByDestination: RouteTypeDeleteByDestination,
+}
+
 // RouteType returns a description of the query routing type used by the primitive
-func (del *Delete) RouteType() string {
-	return delName[del.Opcode]
+func (del *Delete) RouteType() RouteType {
+	return delRouteType[del.Opcode]
 }
 
 // GetKeyspaceName specifies the Keyspace that this primitive routes to.
@@ -68,6 +76,16 @@ func (del *Delete) GetTableName() string {
 	return ""
 }
 
+// HasSideEffects implements the Primitive interface
+func (del *Delete) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory implements the Primitive interface
+func (del *Delete) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // Execute performs a non-streaming exec.
 func (del *Delete) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
 	if del.QueryTimeout != 0 {
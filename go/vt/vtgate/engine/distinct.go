@@ -142,7 +142,7 @@ func (d *Distinct) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.B
 }
 
 // RouteType implements the Primitive interface
-func (d *Distinct) RouteType() string {
+func (d *Distinct) RouteType() RouteType {
 	return d.Source.RouteType()
 }
 
@@ -156,6 +156,17 @@ func (d *Distinct) GetTableName() string {
 	return d.Source.GetTableName()
 }
 
+// HasSideEffects implements the Primitive interface
+func (d *Distinct) HasSideEffects() bool {
+	return inputsHaveSideEffects(d)
+}
+
+// EstimatedMemory implements the Primitive interface
+// It holds the rows it has seen.
+func (d *Distinct) EstimatedMemory() int64 {
+	return primitiveMemory + bufferedRows*rowMemory + inputsMemory(d)
+}
+
 // GetFields implements the Primitive interface
 func (d *Distinct) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	return d.Source.GetFields(vcursor, bindVars)
@@ -171,6 +182,13 @@ func (d *Distinct) Inputs() []Primitive {
 	return []Primitive{d.Source}
 }
 
+// withInputs implements the analyzable interface
+func (d *Distinct) withInputs(inputs []Primitive) Primitive {
+	cp := *d
+	cp.Source = inputs[0]
+	return &cp
+}
+
 func (d *Distinct) description() PrimitiveDescription {
 	return PrimitiveDescription{
 		OperatorType: "Distinct",
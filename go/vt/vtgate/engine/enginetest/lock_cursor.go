@@ -0,0 +1,505 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package enginetest contains test doubles for the primitives of the engine package.
+package enginetest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/sync/errgroup"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var _ engine.VCursor = (*LockTestCursor)(nil)
+var _ engine.SessionActions = (*LockTestCursor)(nil)
+
+// LockTestCursor is an implementation of engine.VCursor for testing the lock primitives.
+// It plays the part of the vtgate session: it keeps track of the advisory locks held,
+// of the reserved connection holding them, and logs the lock queries it receives.
+// The queries unrelated to locks are not supported.
+type LockTestCursor struct {
+	// Ctx is the context of the request. context.Background() is used if it is nil.
+	Ctx context.Context
+	// Shards are the shards of the keyspaces. A keyspace has a single shard "0" if it is empty.
+	Shards []string
+	// Results are returned by the lock queries, in order. An empty result is returned once they are exhausted.
+	Results []*sqltypes.Result
+	// Err is returned by the lock queries instead of the results, if set.
+	Err error
+	// InTx is returned by InTransaction.
+	InTx bool
+	// ReservedConnDisabled is the opposite of ReservedConnEnabled.
+	ReservedConnDisabled bool
+	// LocksDisabled is the opposite of AdvisoryLocksEnabled.
+	LocksDisabled bool
+	// MaxLocks is returned by MaxAdvisoryLocks.
+	MaxLocks int
+	// MaxWait is returned by MaxLockWait.
+	MaxWait time.Duration
+	// ConnID is the id of the reserved connection opened by the first lock query. It defaults to 1.
+	ConnID int64
+
+	mu       sync.Mutex
+	log      []string
+	events   []engine.LockEvent
+	warnings []*querypb.QueryWarning
+	locks    map[string]int64
+	target   *querypb.Target
+	reserved int64
+}
+
+// NewLockTestCursor returns a LockTestCursor with a single shard.
+func NewLockTestCursor() *LockTestCursor {
+	return &LockTestCursor{}
+}
+
+// Log returns the queries received by the cursor.
+func (c *LockTestCursor) Log() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.log...)
+}
+
+// Events returns the lock events reported by the primitives.
+func (c *LockTestCursor) Events() []engine.LockEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]engine.LockEvent(nil), c.events...)
+}
+
+// Warnings returns the warnings recorded in the session.
+func (c *LockTestCursor) Warnings() []*querypb.QueryWarning {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*querypb.QueryWarning(nil), c.warnings...)
+}
+
+// HeldLocks returns the names of the advisory locks held by the session, sorted.
+func (c *LockTestCursor) HeldLocks() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var names []string
+	for name := range c.locks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Context implements the VCursor interface
+func (c *LockTestCursor) Context() context.Context {
+	if c.Ctx == nil {
+		return context.Background()
+	}
+	return c.Ctx
+}
+
+// GetKeyspace implements the VCursor interface
+func (c *LockTestCursor) GetKeyspace() string {
+	return ""
+}
+
+// MaxMemoryRows implements the VCursor interface
+func (c *LockTestCursor) MaxMemoryRows() int {
+	return 0
+}
+
+// ExceedsMaxMemoryRows implements the VCursor interface
+func (c *LockTestCursor) ExceedsMaxMemoryRows(numRows int) bool {
+	return false
+}
+
+// MaxAdvisoryLocks implements the VCursor interface
+func (c *LockTestCursor) MaxAdvisoryLocks() int {
+	return c.MaxLocks
+}
+
+// MaxLockWait implements the VCursor interface
+func (c *LockTestCursor) MaxLockWait() time.Duration {
+	return c.MaxWait
+}
+
+// ReservedConnEnabled implements the VCursor interface
+func (c *LockTestCursor) ReservedConnEnabled() bool {
+	return !c.ReservedConnDisabled
+}
+
+// AdvisoryLocksEnabled implements the VCursor interface
+func (c *LockTestCursor) AdvisoryLocksEnabled() bool {
+	return !c.LocksDisabled
+}
+
+// SetContextTimeout implements the VCursor interface
+func (c *LockTestCursor) SetContextTimeout(timeout time.Duration) context.CancelFunc {
+	ctx, cancel := context.WithTimeout(c.Context(), timeout)
+	restore := c.SetContext(ctx)
+	return func() {
+		cancel()
+		restore()
+	}
+}
+
+// ReservedConnID implements the VCursor interface
+func (c *LockTestCursor) ReservedConnID() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reserved
+}
+
+// DryRun implements the VCursor interface
+func (c *LockTestCursor) DryRun() bool {
+	return false
+}
+
+// OnLockEvent implements the VCursor interface
+func (c *LockTestCursor) OnLockEvent(event engine.LockEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, event)
+}
+
+// SetContext implements the VCursor interface
+func (c *LockTestCursor) SetContext(ctx context.Context) func() {
+	orig := c.Ctx
+	c.Ctx = ctx
+	return func() {
+		c.Ctx = orig
+	}
+}
+
+// ErrorGroupCancellableContext implements the VCursor interface
+func (c *LockTestCursor) ErrorGroupCancellableContext() (*errgroup.Group, func()) {
+	g, ctx := errgroup.WithContext(c.Context())
+	restore := c.SetContext(ctx)
+	return g, restore
+}
+
+// Execute implements the VCursor interface
+func (c *LockTestCursor) Execute(method string, query string, bindvars map[string]*querypb.BindVariable, rollbackOnError bool, co vtgatepb.CommitOrder) (*sqltypes.Result, error) {
+	return nil, unsupported("Execute")
+}
+
+// AutocommitApproval implements the VCursor interface
+func (c *LockTestCursor) AutocommitApproval() bool {
+	return false
+}
+
+// ExecuteMultiShard implements the VCursor interface
+func (c *LockTestCursor) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, rollbackOnError, canAutocommit bool) (*sqltypes.Result, []error) {
+	return nil, []error{unsupported("ExecuteMultiShard")}
+}
+
+// ExecuteStandalone implements the VCursor interface
+func (c *LockTestCursor) ExecuteStandalone(query string, bindvars map[string]*querypb.BindVariable, rs *srvtopo.ResolvedShard) (*sqltypes.Result, error) {
+	return nil, unsupported("ExecuteStandalone")
+}
+
+// StreamExecuteMulti implements the VCursor interface
+func (c *LockTestCursor) StreamExecuteMulti(query string, rss []*srvtopo.ResolvedShard, bindVars []map[string]*querypb.BindVariable, callback func(reply *sqltypes.Result) error) error {
+	return unsupported("StreamExecuteMulti")
+}
+
+// ExecuteKeyspaceID implements the VCursor interface
+func (c *LockTestCursor) ExecuteKeyspaceID(keyspace string, ksid []byte, query string, bindVars map[string]*querypb.BindVariable, rollbackOnError, autocommit bool) (*sqltypes.Result, error) {
+	return nil, unsupported("ExecuteKeyspaceID")
+}
+
+// ResolveDestinations implements the VCursor interface.
+// Keyspace ids resolve to the first shard, shards to themselves.
+func (c *LockTestCursor) ResolveDestinations(keyspace string, ids []*querypb.Value, destinations []key.Destination) ([]*srvtopo.ResolvedShard, [][]*querypb.Value, error) {
+	shards := c.Shards
+	if len(shards) == 0 {
+		shards = []string{"0"}
+	}
+	var rss []*srvtopo.ResolvedShard
+	for _, destination := range destinations {
+		switch d := destination.(type) {
+		case key.DestinationKeyspaceID, key.DestinationAnyShard:
+			rss = append(rss, resolvedShard(keyspace, shards[0]))
+		case key.DestinationShard:
+			rss = append(rss, resolvedShard(keyspace, string(d)))
+		case key.DestinationAllShards:
+			for _, shard := range shards {
+				rss = append(rss, resolvedShard(keyspace, shard))
+			}
+		default:
+			return nil, nil, unsupported(fmt.Sprintf("ResolveDestinations of %v", destination))
+		}
+	}
+	return rss, nil, nil
+}
+
+func resolvedShard(keyspace, shard string) *srvtopo.ResolvedShard {
+	return &srvtopo.ResolvedShard{
+		Target: &querypb.Target{
+			Keyspace:   keyspace,
+			Shard:      shard,
+			TabletType: topodatapb.TabletType_MASTER,
+		},
+	}
+}
+
+// ExecuteVSchema implements the VCursor interface
+func (c *LockTestCursor) ExecuteVSchema(keyspace string, vschemaDDL *sqlparser.AlterVschema) error {
+	return unsupported("ExecuteVSchema")
+}
+
+// SubmitOnlineDDL implements the VCursor interface
+func (c *LockTestCursor) SubmitOnlineDDL(onlineDDl *schema.OnlineDDL) error {
+	return unsupported("SubmitOnlineDDL")
+}
+
+// Session implements the VCursor interface
+func (c *LockTestCursor) Session() engine.SessionActions {
+	return c
+}
+
+// ExecuteLock implements the VCursor interface.
+// The first lock query reserves the connection holding the locks of the session.
+func (c *LockTestCursor) ExecuteLock(rs *srvtopo.ResolvedShard, query *querypb.BoundQuery) (*sqltypes.Result, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.log = append(c.log, fmt.Sprintf("ExecuteLock %s/%s: %s", rs.Target.Keyspace, rs.Target.Shard, query.Sql))
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	if c.reserved == 0 {
+		c.reserved = c.ConnID
+		if c.reserved == 0 {
+			c.reserved = 1
+		}
+		c.target = rs.Target
+	}
+	if len(c.Results) == 0 {
+		return &sqltypes.Result{}, nil
+	}
+	qr := c.Results[0]
+	c.Results = c.Results[1:]
+	return qr, nil
+}
+
+// SetLockWaitTimeout implements the VCursor interface
+func (c *LockTestCursor) SetLockWaitTimeout(rs *srvtopo.ResolvedShard, timeout time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.log = append(c.log, fmt.Sprintf("SetLockWaitTimeout %s/%s: %v", rs.Target.Keyspace, rs.Target.Shard, timeout))
+	return c.Err
+}
+
+// InTransactionAndIsDML implements the VCursor interface
+func (c *LockTestCursor) InTransactionAndIsDML() bool {
+	return false
+}
+
+// InTransaction implements the VCursor interface
+func (c *LockTestCursor) InTransaction() bool {
+	return c.InTx
+}
+
+// TargetReadOnly implements the VCursor interface
+func (c *LockTestCursor) TargetReadOnly(target *querypb.Target) bool {
+	return false
+}
+
+// SessionDestination implements the VCursor interface
+func (c *LockTestCursor) SessionDestination() (string, key.Destination) {
+	return "", nil
+}
+
+// LookupRowLockShardSession implements the VCursor interface
+func (c *LockTestCursor) LookupRowLockShardSession() vtgatepb.CommitOrder {
+	return vtgatepb.CommitOrder_NORMAL
+}
+
+// FindRoutedTable implements the VCursor interface
+func (c *LockTestCursor) FindRoutedTable(tablename sqlparser.TableName) (*vindexes.Table, error) {
+	return nil, unsupported("FindRoutedTable")
+}
+
+// RecordWarning implements the SessionActions interface
+func (c *LockTestCursor) RecordWarning(warning *querypb.QueryWarning) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = append(c.warnings, warning)
+}
+
+// SetTarget implements the SessionActions interface
+func (c *LockTestCursor) SetTarget(target string) error {
+	return unsupported("SetTarget")
+}
+
+// SetUDV implements the SessionActions interface
+func (c *LockTestCursor) SetUDV(key string, value interface{}) error {
+	return unsupported("SetUDV")
+}
+
+// SetSysVar implements the SessionActions interface
+func (c *LockTestCursor) SetSysVar(name string, expr string) {
+}
+
+// NeedsReservedConn implements the SessionActions interface
+func (c *LockTestCursor) NeedsReservedConn() {
+}
+
+// InReservedConn implements the SessionActions interface
+func (c *LockTestCursor) InReservedConn() bool {
+	return c.ReservedConnID() != 0
+}
+
+// ShardSession implements the SessionActions interface
+func (c *LockTestCursor) ShardSession() []*srvtopo.ResolvedShard {
+	return nil
+}
+
+// SetAutocommit implements the SessionActions interface
+func (c *LockTestCursor) SetAutocommit(bool) error {
+	return unsupported("SetAutocommit")
+}
+
+// SetClientFoundRows implements the SessionActions interface
+func (c *LockTestCursor) SetClientFoundRows(bool) error {
+	return unsupported("SetClientFoundRows")
+}
+
+// SetSkipQueryPlanCache implements the SessionActions interface
+func (c *LockTestCursor) SetSkipQueryPlanCache(bool) error {
+	return unsupported("SetSkipQueryPlanCache")
+}
+
+// SetSQLSelectLimit implements the SessionActions interface
+func (c *LockTestCursor) SetSQLSelectLimit(int64) error {
+	return unsupported("SetSQLSelectLimit")
+}
+
+// SetTransactionMode implements the SessionActions interface
+func (c *LockTestCursor) SetTransactionMode(vtgatepb.TransactionMode) {
+}
+
+// SetWorkload implements the SessionActions interface
+func (c *LockTestCursor) SetWorkload(querypb.ExecuteOptions_Workload) {
+}
+
+// SetFoundRows implements the SessionActions interface
+func (c *LockTestCursor) SetFoundRows(uint64) {
+}
+
+// SetDDLStrategy implements the SessionActions interface
+func (c *LockTestCursor) SetDDLStrategy(string) {
+}
+
+// GetDDLStrategy implements the SessionActions interface
+func (c *LockTestCursor) GetDDLStrategy() string {
+	return ""
+}
+
+// SetReadAfterWriteGTID implements the SessionActions interface
+func (c *LockTestCursor) SetReadAfterWriteGTID(string) {
+}
+
+// SetReadAfterWriteTimeout implements the SessionActions interface
+func (c *LockTestCursor) SetReadAfterWriteTimeout(float64) {
+}
+
+// SetSessionTrackGTIDs implements the SessionActions interface
+func (c *LockTestCursor) SetSessionTrackGTIDs(bool) {
+}
+
+// SetReleaseLocksOnCommit implements the SessionActions interface
+func (c *LockTestCursor) SetReleaseLocksOnCommit(bool) error {
+	return unsupported("SetReleaseLocksOnCommit")
+}
+
+// AdvisoryLockCount implements the SessionActions interface
+func (c *LockTestCursor) AdvisoryLockCount(name string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.locks[name]
+}
+
+// AdvisoryLocksHeld implements the SessionActions interface
+func (c *LockTestCursor) AdvisoryLocksHeld() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.locks)
+}
+
+// AdvisoryLocks implements the SessionActions interface
+func (c *LockTestCursor) AdvisoryLocks() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.locks) == 0 {
+		return nil
+	}
+	locks := make(map[string]int64, len(c.locks))
+	for name, count := range c.locks {
+		locks[name] = count
+	}
+	return locks
+}
+
+// LockSessionTarget implements the SessionActions interface
+func (c *LockTestCursor) LockSessionTarget() *querypb.Target {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.target
+}
+
+// SetAdvisoryLockCount implements the SessionActions interface
+func (c *LockTestCursor) SetAdvisoryLockCount(name string, count int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if count <= 0 {
+		delete(c.locks, name)
+		return
+	}
+	if c.locks == nil {
+		c.locks = make(map[string]int64)
+	}
+	c.locks[name] = count
+}
+
+// ResetAdvisoryLocks implements the SessionActions interface
+func (c *LockTestCursor) ResetAdvisoryLocks() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.locks = nil
+}
+
+// SessionKey implements the SessionActions interface
+func (c *LockTestCursor) SessionKey() interface{} {
+	return c
+}
+
+func unsupported(method string) error {
+	return vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "LockTestCursor: %s is not supported", method)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enginetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func newLock(t *testing.T, query string, lf engine.LockFunc) *engine.Lock {
+	l, err := engine.NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, query, engine.WithLockFuncs(lf))
+	require.NoError(t, err)
+	return l
+}
+
+func lockResult(column, value string) *sqltypes.Result {
+	return sqltypes.MakeTestResult(sqltypes.MakeTestFields(column, "int64"), value)
+}
+
+func TestLockTestCursorAcquireRelease(t *testing.T) {
+	name := sqltypes.PlanValue{Value: sqltypes.NewVarChar("acquire release")}
+	getLock := newLock(t, "select get_lock('acquire release', 10) from dual", engine.LockFunc{Type: engine.GetLock, Name: name})
+	releaseLock := newLock(t, "select release_lock('acquire release') from dual", engine.LockFunc{Type: engine.ReleaseLock, Name: name})
+
+	c := &LockTestCursor{
+		ConnID: 42,
+		Results: []*sqltypes.Result{
+			lockResult("get_lock('acquire release', 10)", "1"),
+			lockResult("release_lock('acquire release')", "1"),
+		},
+	}
+	_, err := getLock.Execute(c, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acquire release"}, c.HeldLocks())
+	assert.EqualValues(t, 1, c.AdvisoryLockCount("acquire release"))
+	assert.EqualValues(t, 42, c.ReservedConnID())
+	assert.True(t, c.InReservedConn())
+	assert.Equal(t, "0", c.LockSessionTarget().Shard)
+
+	_, err = releaseLock.Execute(c, nil, false)
+	require.NoError(t, err)
+	assert.Empty(t, c.HeldLocks())
+	// the connection stays reserved once the locks are released.
+	assert.EqualValues(t, 42, c.ReservedConnID())
+
+	assert.Equal(t, []string{
+		"ExecuteLock ks/0: select get_lock('acquire release', 10) from dual",
+		"ExecuteLock ks/0: select release_lock('acquire release') from dual",
+	}, c.Log())
+	assert.Equal(t, []engine.LockEvent{
+		{Type: engine.GetLock, Name: "acquire release", Outcome: "1"},
+		{Type: engine.ReleaseLock, Name: "acquire release", Outcome: "1"},
+	}, c.Events())
+}
+
+func TestLockTestCursorReleaseAll(t *testing.T) {
+	c := &LockTestCursor{Results: []*sqltypes.Result{
+		lockResult("get_lock('release all 1', 10)", "1"),
+		lockResult("get_lock('release all 2', 10)", "1"),
+		lockResult("release_all_locks()", "2"),
+	}}
+	for _, name := range []string{"release all 1", "release all 2"} {
+		l := newLock(t, "select get_lock('"+name+"', 10) from dual",
+			engine.LockFunc{Type: engine.GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar(name)}})
+		_, err := l.Execute(c, nil, false)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"release all 1", "release all 2"}, c.HeldLocks())
+
+	releaseAll := newLock(t, "select release_all_locks() from dual", engine.LockFunc{Type: engine.ReleaseAllLocks})
+	_, err := releaseAll.Execute(c, nil, false)
+	require.NoError(t, err)
+	assert.Empty(t, c.HeldLocks())
+}
+
+func TestLockTestCursorErrors(t *testing.T) {
+	l := newLock(t, "select get_lock('errors', 10) from dual",
+		engine.LockFunc{Type: engine.GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("errors")}})
+
+	c := &LockTestCursor{Err: errors.New("lock wait timeout")}
+	_, err := l.Execute(c, nil, false)
+	require.EqualError(t, err, "lock wait timeout")
+	assert.Empty(t, c.HeldLocks())
+	assert.Zero(t, c.ReservedConnID())
+
+	c = &LockTestCursor{ReservedConnDisabled: true}
+	_, err = l.Execute(c, nil, false)
+	require.EqualError(t, err, "advisory locks require reserved connections, which are disabled")
+	assert.Empty(t, c.Log())
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+var _ Primitive = (*ExplainAnalyze)(nil)
+
+// ExplainAnalyze is the primitive of EXPLAIN ANALYZE. It executes its input in analyze mode,
+// see ExecuteAnalyze, and returns the plan of the input, where every primitive carries the
+// number of rows it produced and the time it took, instead of the result.
+type ExplainAnalyze struct {
+	Input Primitive
+}
+
+var explainAnalyzeFields = []*querypb.Field{{Name: "EXPLAIN", Type: querypb.Type_VARCHAR}}
+
+// RouteType implements the Primitive interface
+func (e *ExplainAnalyze) RouteType() RouteType {
+	return e.Input.RouteType()
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (e *ExplainAnalyze) GetKeyspaceName() string {
+	return e.Input.GetKeyspaceName()
+}
+
+// GetTableName implements the Primitive interface
+func (e *ExplainAnalyze) GetTableName() string {
+	return e.Input.GetTableName()
+}
+
+// HasSideEffects implements the Primitive interface
+// The input is executed.
+func (e *ExplainAnalyze) HasSideEffects() bool {
+	return e.Input.HasSideEffects()
+}
+
+// EstimatedMemory implements the Primitive interface
+func (e *ExplainAnalyze) EstimatedMemory() int64 {
+	return primitiveMemory + inputsMemory(e)
+}
+
+// NeedsTransaction implements the Primitive interface
+func (e *ExplainAnalyze) NeedsTransaction() bool {
+	return e.Input.NeedsTransaction()
+}
+
+// Execute implements the Primitive interface
+func (e *ExplainAnalyze) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	_, desc, err := ExecuteAnalyze(vcursor, e.Input, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := json.MarshalIndent(desc, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{
+		Fields:       explainAnalyzeFields,
+		Rows:         [][]sqltypes.Value{{sqltypes.NewVarChar(string(plan))}},
+		RowsAffected: 1,
+	}, nil
+}
+
+// StreamExecute implements the Primitive interface
+func (e *ExplainAnalyze) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	qr, err := e.Execute(vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// GetFields implements the Primitive interface
+func (e *ExplainAnalyze) GetFields(VCursor, map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: explainAnalyzeFields}, nil
+}
+
+// Inputs implements the Primitive interface
+func (e *ExplainAnalyze) Inputs() []Primitive {
+	return []Primitive{e.Input}
+}
+
+func (e *ExplainAnalyze) description() PrimitiveDescription {
+	return PrimitiveDescription{OperatorType: "ExplainAnalyze"}
+}
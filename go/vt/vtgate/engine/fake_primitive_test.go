@@ -51,8 +51,16 @@ func (f *fakePrimitive) rewind() {
 	f.log = nil
 }
 
-func (f *fakePrimitive) RouteType() string {
-	return "Fake"
+func (f *fakePrimitive) RouteType() RouteType {
+	return NoRouteType
+}
+
+func (f *fakePrimitive) HasSideEffects() bool {
+	return false
+}
+
+func (f *fakePrimitive) EstimatedMemory() int64 {
+	return primitiveMemory
 }
 
 func (f *fakePrimitive) GetKeyspaceName() string {
@@ -48,6 +48,11 @@ import (
 
 var testMaxMemoryRows = 100
 var testIgnoreMaxMemoryRows = false
+var testMaxAdvisoryLocks = 0
+
+var testMaxLockWait time.Duration
+var testReservedConnDisabled = false
+var testAdvisoryLocksDisabled = false
 
 var _ VCursor = (*noopVCursor)(nil)
 var _ SessionActions = (*noopVCursor)(nil)
@@ -77,6 +82,38 @@ func (t noopVCursor) SetSessionTrackGTIDs(b bool) {
 	panic("implement me")
 }
 
+func (t noopVCursor) SetReleaseLocksOnCommit(bool) error {
+	panic("implement me")
+}
+
+func (t noopVCursor) AdvisoryLockCount(name string) int64 {
+	panic("implement me")
+}
+
+func (t noopVCursor) AdvisoryLocksHeld() int {
+	panic("implement me")
+}
+
+func (t noopVCursor) AdvisoryLocks() map[string]int64 {
+	panic("implement me")
+}
+
+func (t noopVCursor) LockSessionTarget() *querypb.Target {
+	panic("implement me")
+}
+
+func (t noopVCursor) SetAdvisoryLockCount(name string, count int64) {
+	panic("implement me")
+}
+
+func (t noopVCursor) ResetAdvisoryLocks() {
+	panic("implement me")
+}
+
+func (t noopVCursor) SessionKey() interface{} {
+	panic("implement me")
+}
+
 func (t noopVCursor) LookupRowLockShardSession() vtgatepb.CommitOrder {
 	panic("implement me")
 }
@@ -89,6 +126,18 @@ func (t noopVCursor) InTransactionAndIsDML() bool {
 	panic("implement me")
 }
 
+func (t noopVCursor) InTransaction() bool {
+	panic("implement me")
+}
+
+func (t noopVCursor) SessionDestination() (string, key.Destination) {
+	panic("implement me")
+}
+
+func (t noopVCursor) TargetReadOnly(target *querypb.Target) bool {
+	panic("implement me")
+}
+
 func (t noopVCursor) FindRoutedTable(sqlparser.TableName) (*vindexes.Table, error) {
 	panic("implement me")
 }
@@ -97,6 +146,10 @@ func (t noopVCursor) ExecuteLock(rs *srvtopo.ResolvedShard, query *querypb.Bound
 	panic("implement me")
 }
 
+func (t noopVCursor) SetLockWaitTimeout(rs *srvtopo.ResolvedShard, timeout time.Duration) error {
+	panic("implement me")
+}
+
 func (t noopVCursor) NeedsReservedConn() {
 }
 
@@ -166,6 +219,22 @@ func (t noopVCursor) ExceedsMaxMemoryRows(numRows int) bool {
 	return !testIgnoreMaxMemoryRows && numRows > testMaxMemoryRows
 }
 
+func (t noopVCursor) MaxAdvisoryLocks() int {
+	return testMaxAdvisoryLocks
+}
+
+func (t noopVCursor) MaxLockWait() time.Duration {
+	return testMaxLockWait
+}
+
+func (t noopVCursor) ReservedConnEnabled() bool {
+	return !testReservedConnDisabled
+}
+
+func (t noopVCursor) AdvisoryLocksEnabled() bool {
+	return !testAdvisoryLocksDisabled
+}
+
 func (t noopVCursor) GetKeyspace() string {
 	return ""
 }
@@ -174,6 +243,22 @@ func (t noopVCursor) SetContextTimeout(timeout time.Duration) context.CancelFunc
 	return func() {}
 }
 
+func (t noopVCursor) ReservedConnID() int64 {
+	panic("implement me")
+}
+
+func (t noopVCursor) DryRun() bool {
+	panic("implement me")
+}
+
+func (t noopVCursor) OnLockEvent(event LockEvent) {
+	panic("implement me")
+}
+
+func (t noopVCursor) SetContext(ctx context.Context) func() {
+	panic("implement me")
+}
+
 func (t noopVCursor) ErrorGroupCancellableContext() (*errgroup.Group, func()) {
 	g, ctx := errgroup.WithContext(t.ctx)
 	t.ctx = ctx
@@ -243,6 +328,19 @@ type loggingVCursor struct {
 	resolvedTargetTabletType topodatapb.TabletType
 
 	tableRoutes tableRoutes
+
+	advisoryLocks map[string]int64
+	lockTarget    *querypb.Target
+	inTransaction bool
+
+	sessionKeyspace    string
+	sessionDestination key.Destination
+
+	targetReadOnly bool
+	reservedConn   bool
+	dryRun         bool
+	reservedConnID int64
+	lockEvents     []LockEvent
 }
 
 type tableRoutes struct {
@@ -257,6 +355,18 @@ func (f *loggingVCursor) InTransactionAndIsDML() bool {
 	return false
 }
 
+func (f *loggingVCursor) InTransaction() bool {
+	return f.inTransaction
+}
+
+func (f *loggingVCursor) SessionDestination() (string, key.Destination) {
+	return f.sessionKeyspace, f.sessionDestination
+}
+
+func (f *loggingVCursor) TargetReadOnly(target *querypb.Target) bool {
+	return f.targetReadOnly
+}
+
 func (f *loggingVCursor) LookupRowLockShardSession() vtgatepb.CommitOrder {
 	panic("implement me")
 }
@@ -271,10 +381,66 @@ func (f *loggingVCursor) SetSysVar(name string, expr string) {
 }
 
 func (f *loggingVCursor) NeedsReservedConn() {
+	f.reservedConn = true
+}
+
+func (f *loggingVCursor) ExecuteLock(rs *srvtopo.ResolvedShard, query *querypb.BoundQuery) (*sqltypes.Result, error) {
+	f.log = append(f.log, fmt.Sprintf("ExecuteLock %s.%s: %s {%s}", rs.Target.Keyspace, rs.Target.Shard, query.Sql, printBindVars(query.BindVariables)))
+	f.lockTarget = rs.Target
+	return f.nextResult()
+}
+
+func (f *loggingVCursor) SetLockWaitTimeout(rs *srvtopo.ResolvedShard, timeout time.Duration) error {
+	f.log = append(f.log, fmt.Sprintf("SetLockWaitTimeout %s.%s: %v", rs.Target.Keyspace, rs.Target.Shard, timeout))
+	return nil
+}
+
+func (f *loggingVCursor) AdvisoryLockCount(name string) int64 {
+	return f.advisoryLocks[name]
+}
+
+func (f *loggingVCursor) AdvisoryLocksHeld() int {
+	return len(f.advisoryLocks)
+}
+
+func (f *loggingVCursor) AdvisoryLocks() map[string]int64 {
+	if len(f.advisoryLocks) == 0 {
+		return nil
+	}
+	locks := make(map[string]int64, len(f.advisoryLocks))
+	for name, count := range f.advisoryLocks {
+		locks[name] = count
+	}
+	return locks
+}
+
+func (f *loggingVCursor) LockSessionTarget() *querypb.Target {
+	return f.lockTarget
+}
+
+func (f *loggingVCursor) SetAdvisoryLockCount(name string, count int64) {
+	f.log = append(f.log, fmt.Sprintf("SetAdvisoryLockCount %s %d", name, count))
+	if count <= 0 {
+		delete(f.advisoryLocks, name)
+		return
+	}
+	if f.advisoryLocks == nil {
+		f.advisoryLocks = make(map[string]int64)
+	}
+	f.advisoryLocks[name] = count
+}
+
+func (f *loggingVCursor) ResetAdvisoryLocks() {
+	f.log = append(f.log, "ResetAdvisoryLocks")
+	f.advisoryLocks = nil
+}
+
+func (f *loggingVCursor) SessionKey() interface{} {
+	return f
 }
 
 func (f *loggingVCursor) InReservedConn() bool {
-	panic("implement me")
+	return f.reservedConn
 }
 
 func (f *loggingVCursor) ShardSession() []*srvtopo.ResolvedShard {
@@ -295,13 +461,33 @@ func (f *loggingVCursor) SetTarget(target string) error {
 }
 
 func (f *loggingVCursor) Context() context.Context {
-	return context.Background()
+	return f.noopVCursor.Context()
 }
 
 func (f *loggingVCursor) SetContextTimeout(time.Duration) context.CancelFunc {
 	return func() {}
 }
 
+func (f *loggingVCursor) ReservedConnID() int64 {
+	return f.reservedConnID
+}
+
+func (f *loggingVCursor) DryRun() bool {
+	return f.dryRun
+}
+
+func (f *loggingVCursor) OnLockEvent(event LockEvent) {
+	f.lockEvents = append(f.lockEvents, event)
+}
+
+func (f *loggingVCursor) SetContext(ctx context.Context) func() {
+	origCtx := f.ctx
+	f.ctx = ctx
+	return func() {
+		f.ctx = origCtx
+	}
+}
+
 func (f *loggingVCursor) ErrorGroupCancellableContext() (*errgroup.Group, func()) {
 	panic("implement me")
 }
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/cache"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// FieldsCache caches the fields returned by GetFields for the lock primitives, by plan key,
+// so that preparing the same lock statement again does not send another field query.
+// The fields of a lock query only depend on the query, but the cache is meant to be
+// cleared along with the plan cache, when the schema changes.
+type FieldsCache struct {
+	fields *cache.LRUCache
+}
+
+// cachedFields is the value of the FieldsCache entries.
+type cachedFields struct {
+	qr *sqltypes.Result
+}
+
+// Size is defined so that cachedFields can be given to a cache.LRUCache.
+func (c cachedFields) Size() int {
+	return 1
+}
+
+// NewFieldsCache creates a FieldsCache holding the fields of at most capacity plans.
+func NewFieldsCache(capacity int64) *FieldsCache {
+	return &FieldsCache{fields: cache.NewLRUCache(capacity)}
+}
+
+// GetFields returns the fields of the primitive, probed with GetFieldsContext so that ctx bounds
+// the field queries. The fields of the lock primitives come from the cache when possible.
+// A nil FieldsCache does not cache anything.
+func (c *FieldsCache) GetFields(ctx context.Context, p Primitive, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	if c == nil || !staticFields(p) {
+		return GetFieldsContext(ctx, p, vcursor, bindVars)
+	}
+	key := PlanKey(p)
+	if v, ok := c.fields.Get(key); ok {
+		return v.(cachedFields).qr.Copy(), nil
+	}
+	qr, err := GetFieldsContext(ctx, p, vcursor, bindVars)
+	if err != nil {
+		return nil, err
+	}
+	c.fields.Set(key, cachedFields{qr: qr.Copy()})
+	return qr, nil
+}
+
+// Clear empties the cache.
+func (c *FieldsCache) Clear() {
+	if c == nil {
+		return
+	}
+	c.fields.Clear()
+}
+
+// staticFields returns true if the fields of the primitive only depend on its plan.
+func staticFields(p Primitive) bool {
+	switch p.(type) {
+	case *Lock, *TryLock, *LockStatus:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestFieldsCache(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}}))
+	require.NoError(t, err)
+	fields := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"))
+	vc := &loggingVCursor{results: []*sqltypes.Result{fields, fields}}
+	c := NewFieldsCache(10)
+
+	qr1, err := c.GetFields(context.Background(), l, vc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: fields.Fields}, qr1)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteStandalone select get_lock('lock name', 10) from dual where 1 != 1  ks -20",
+	})
+
+	// the same plan, e.g. prepared again, gets the same fields without any field query.
+	vc.Rewind()
+	same, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}}))
+	require.NoError(t, err)
+	qr2, err := c.GetFields(context.Background(), same, vc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, qr1, qr2)
+	vc.ExpectLog(t, nil)
+
+	// once cleared, the fields are fetched again.
+	c.Clear()
+	_, err = c.GetFields(context.Background(), l, vc, nil)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteStandalone select get_lock('lock name', 10) from dual where 1 != 1  ks -20",
+	})
+
+	// the fields of the other primitives depend on the schema, they are not cached.
+	route := NewRoute(SelectUnsharded, &vindexes.Keyspace{Name: "ks"}, "select id from t", "select id from t where 1 != 1")
+	routeFields := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"))
+	vc = &loggingVCursor{shards: []string{"0"}, results: []*sqltypes.Result{routeFields, routeFields}}
+	for i := 0; i < 2; i++ {
+		_, err = c.GetFields(context.Background(), route, vc, nil)
+		require.NoError(t, err)
+	}
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationAnyShard()",
+		"ExecuteMultiShard ks.0: select id from t where 1 != 1 {} false false",
+		"ResolveDestinations ks [] Destinations:DestinationAnyShard()",
+		"ExecuteMultiShard ks.0: select id from t where 1 != 1 {} false false",
+	})
+
+	// the field queries are bounded by the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Clear()
+	vc = &loggingVCursor{}
+	_, err = c.GetFields(ctx, l, vc, nil)
+	require.EqualError(t, err, "GetFields: context canceled")
+	vc.ExpectLog(t, nil)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*ForUpdate)(nil)
+
+// ForUpdate primitive executes a locking read, SELECT ... FOR UPDATE, within a transaction.
+// The row locks are held by the connection of the transaction, so the session is marked
+// as needing a reserved connection, and the read is never streamed through another connection.
+// The executor begins the transaction, as the primitive needs one.
+type ForUpdate struct {
+	// Input is the primitive executing the locking read.
+	Input Primitive
+
+	txNeeded
+}
+
+// RouteType is part of the Primitive interface
+func (f *ForUpdate) RouteType() RouteType {
+	return f.Input.RouteType()
+}
+
+// GetKeyspaceName is part of the Primitive interface
+func (f *ForUpdate) GetKeyspaceName() string {
+	return f.Input.GetKeyspaceName()
+}
+
+// GetTableName is part of the Primitive interface
+func (f *ForUpdate) GetTableName() string {
+	return f.Input.GetTableName()
+}
+
+// HasSideEffects is part of the Primitive interface.
+// It locks the rows it reads until the transaction ends.
+func (f *ForUpdate) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory is part of the Primitive interface.
+func (f *ForUpdate) EstimatedMemory() int64 {
+	return primitiveMemory + inputsMemory(f)
+}
+
+// Execute is part of the Primitive interface
+func (f *ForUpdate) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if !vcursor.InTransaction() {
+		return nil, vterrors.New(vtrpc.Code_INTERNAL, "for update primitive must be executed within a transaction")
+	}
+	vcursor.Session().NeedsReservedConn()
+	return f.Input.Execute(vcursor, bindVars, wantfields)
+}
+
+// StreamExecute is part of the Primitive interface
+func (f *ForUpdate) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	// streaming queries do not use the connections of the transaction.
+	qr, err := f.Execute(vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// GetFields is part of the Primitive interface.
+// The fields come from the field query of the input, which does not lock any row:
+// unlike Execute, it does not need a transaction nor a reserved connection.
+func (f *ForUpdate) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return f.Input.GetFields(vcursor, bindVars)
+}
+
+// Inputs is part of the Primitive interface
+func (f *ForUpdate) Inputs() []Primitive {
+	return []Primitive{f.Input}
+}
+
+// withInputs implements the analyzable interface
+func (f *ForUpdate) withInputs(inputs []Primitive) Primitive {
+	cp := *f
+	cp.Input = inputs[0]
+	return &cp
+}
+
+func (f *ForUpdate) description() PrimitiveDescription {
+	return PrimitiveDescription{OperatorType: "ForUpdate"}
+}
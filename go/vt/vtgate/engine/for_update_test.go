@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestForUpdate(t *testing.T) {
+	route := NewRoute(SelectUnsharded, &vindexes.Keyspace{Name: "ks"}, "select id from t where id = 1 for update", "select id from t where 1 != 1")
+	f := &ForUpdate{Input: route}
+	assert.True(t, f.NeedsTransaction())
+	assert.Equal(t, []Primitive{route}, f.Inputs())
+
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "1")
+	vc := &loggingVCursor{shards: []string{"0"}, results: []*sqltypes.Result{result, result}, inTransaction: true}
+	qr, err := f.Execute(vc, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, result, qr)
+	// the locking read keeps using the connection of the transaction.
+	assert.True(t, vc.InReservedConn())
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationAnyShard()",
+		"ExecuteMultiShard ks.0: select id from t where id = 1 for update {} false false",
+	})
+
+	// streaming goes through the same connection.
+	vc.Rewind()
+	var got []*sqltypes.Result
+	err = f.StreamExecute(vc, nil, true, func(qr *sqltypes.Result) error {
+		got = append(got, qr)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*sqltypes.Result{result}, got)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationAnyShard()",
+		"ExecuteMultiShard ks.0: select id from t where id = 1 for update {} false false",
+	})
+
+	vc = &loggingVCursor{shards: []string{"0"}}
+	_, err = f.Execute(vc, nil, true)
+	require.EqualError(t, err, "for update primitive must be executed within a transaction")
+	vc.ExpectLog(t, nil)
+}
+
+func TestForUpdateGetFields(t *testing.T) {
+	route := NewRoute(SelectUnsharded, &vindexes.Keyspace{Name: "ks"}, "select id from t where id = 1 for update", "select id from t where 1 != 1")
+	f := &ForUpdate{Input: route}
+
+	fields := sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"))
+	// the fields are fetched outside of a transaction.
+	vc := &loggingVCursor{shards: []string{"0"}, results: []*sqltypes.Result{fields}}
+	qr, err := f.GetFields(vc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, fields, qr)
+	assert.False(t, vc.InReservedConn())
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationAnyShard()",
+		"ExecuteMultiShard ks.0: select id from t where 1 != 1 {} false false",
+	})
+}
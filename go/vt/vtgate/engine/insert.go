@@ -158,6 +158,12 @@ var insName = map[InsertOpcode]string{
 	InsertShardedIgnore: "InsertShardedIgnore",
 }
 
+var insRouteType = map[InsertOpcode]RouteType{
+	InsertUnsharded:     RouteTypeInsertUnsharded,
+	InsertSharded:       RouteTypeInsertSharded,
+	InsertShardedIgnore: RouteTypeInsertShardedIgnore,
+}
+
 // String returns the opcode
 func (code InsertOpcode) String() string {
 	return strings.ReplaceAll(insName[code], "Insert", "")
@@ -170,8 +176,8 @@ func (code InsertOpcode) MarshalJSON() ([]byte, error) {
 }
 
 // RouteType returns a description of the query routing type used by the primitive
-func (ins *Insert) RouteType() string {
-	return insName[ins.Opcode]
+func (ins *Insert) RouteType() RouteType {
+	return insRouteType[ins.Opcode]
 }
 
 // GetKeyspaceName specifies the Keyspace that this primitive routes to.
@@ -187,6 +193,16 @@ func (ins *Insert) GetTableName() string {
 	return ""
 }
 
+// HasSideEffects implements the Primitive interface
+func (ins *Insert) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory implements the Primitive interface
+func (ins *Insert) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // Execute performs a non-streaming exec.
 func (ins *Insert) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	if ins.QueryTimeout != 0 {
@@ -176,6 +176,13 @@ func (jn *Join) Inputs() []Primitive {
 	return []Primitive{jn.Left, jn.Right}
 }
 
+// withInputs implements the analyzable interface
+func (jn *Join) withInputs(inputs []Primitive) Primitive {
+	cp := *jn
+	cp.Left, cp.Right = inputs[0], inputs[1]
+	return &cp
+}
+
 func joinFields(lfields, rfields []*querypb.Field, cols []int) []*querypb.Field {
 	fields := make([]*querypb.Field, len(cols))
 	for i, index := range cols {
@@ -227,8 +234,8 @@ func (code JoinOpcode) MarshalJSON() ([]byte, error) {
 }
 
 // RouteType returns a description of the query routing type used by the primitive
-func (jn *Join) RouteType() string {
-	return "Join"
+func (jn *Join) RouteType() RouteType {
+	return RouteTypeJoin
 }
 
 // GetKeyspaceName specifies the Keyspace that this primitive routes to.
@@ -244,6 +251,17 @@ func (jn *Join) GetTableName() string {
 	return jn.Left.GetTableName() + "_" + jn.Right.GetTableName()
 }
 
+// HasSideEffects implements the Primitive interface
+func (jn *Join) HasSideEffects() bool {
+	return inputsHaveSideEffects(jn)
+}
+
+// EstimatedMemory implements the Primitive interface
+// It holds the rows of its left side while it executes the right side.
+func (jn *Join) EstimatedMemory() int64 {
+	return primitiveMemory + bufferedRows*rowMemory + inputsMemory(jn)
+}
+
 func (jn *Join) NeedsTransaction() bool {
 	return jn.Right.NeedsTransaction() || jn.Left.NeedsTransaction()
 }
@@ -37,7 +37,7 @@ type Limit struct {
 }
 
 // RouteType returns a description of the query routing type used by the primitive
-func (l *Limit) RouteType() string {
+func (l *Limit) RouteType() RouteType {
 	return l.Input.RouteType()
 }
 
@@ -51,6 +51,16 @@ func (l *Limit) GetTableName() string {
 	return l.Input.GetTableName()
 }
 
+// HasSideEffects implements the Primitive interface
+func (l *Limit) HasSideEffects() bool {
+	return inputsHaveSideEffects(l)
+}
+
+// EstimatedMemory implements the Primitive interface
+func (l *Limit) EstimatedMemory() int64 {
+	return primitiveMemory + inputsMemory(l)
+}
+
 // Execute satisfies the Primtive interface.
 func (l *Limit) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	count, err := l.fetchCount(bindVars)
@@ -165,6 +175,13 @@ func (l *Limit) Inputs() []Primitive {
 	return []Primitive{l.Input}
 }
 
+// withInputs implements the analyzable interface
+func (l *Limit) withInputs(inputs []Primitive) Primitive {
+	cp := *l
+	cp.Input = inputs[0]
+	return &cp
+}
+
 //NeedsTransaction implements the Primitive interface.
 func (l *Limit) NeedsTransaction() bool {
 	return l.Input.NeedsTransaction()
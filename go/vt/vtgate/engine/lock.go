@@ -17,35 +17,429 @@ limitations under the License.
 package engine
 
 import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/context"
+
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
 var _ Primitive = (*Lock)(nil)
 
-//Lock primitive will execute sql containing lock functions
+// maxLockNameLength is the maximum length of the name of an advisory lock in MySQL.
+const maxLockNameLength = 64
+
+// LockConnIDVariable is the user defined variable of the session holding the id of the
+// reserved connection holding its advisory locks, when reported. See Lock.ReportConnID.
+const LockConnIDVariable = "vt_lock_connection_id"
+
+//Lock primitive will execute sql containing lock functions.
+// Use NewLock to create one.
 type Lock struct {
 	// Keyspace specifies the keyspace to send the query to.
 	Keyspace *vindexes.Keyspace
 
 	// TargetDestination specifies an explicit target destination to send the query to.
+	// It is not set when the destination is resolved through Vindex.
 	TargetDestination key.Destination
 
+	// Vindex specifies the vindex used to resolve the destination from Values, so that
+	// the locks for a given logical key, a tenant for instance, are always acquired on
+	// the same shard.
+	Vindex vindexes.SingleColumn
+	// Values specifies the vindex value to use for routing.
+	Values []sqltypes.PlanValue
+
 	// Query specifies the query to be executed.
 	Query string
 
+	// TableName is the table the lock query is written against, as reported by GetTableName.
+	// When empty, "dual" is reported.
+	TableName string
+
+	// ColumnAlias is the name of the column of the first locking function in the fields and
+	// results, in place of the name derived from its select expression. When there is no
+	// locking function, it names the first column. It is not used when empty.
+	ColumnAlias string
+
+	// Timeout is the optional timeout to apply to the lock query.
+	Timeout time.Duration
+
+	// PrimaryOnly specifies that the lock must only be acquired on a primary tablet.
+	PrimaryOnly bool
+
+	// Retries is the number of times the lock query is retried when it fails
+	// with a retryable error. See ClassifyLockError.
+	Retries int
+
+	// Breaker is the optional circuit breaker of the lock acquisitions. See LockBreaker.
+	Breaker *LockBreaker
+
+	// Fair makes the acquisitions of a lock through this vtgate wait for it in arrival order,
+	// rather than in the order MySQL wakes up its waiters.
+	Fair bool
+
+	// Namespace is prefixed to the lock names of the query, so that applications
+	// sharing a keyspace do not collide on the names of their locks. RELEASE_ALL_LOCKS
+	// still releases the locks of every namespace held by the session.
+	Namespace string
+
+	// ReportConnID makes the id of the reserved connection holding the advisory locks of the
+	// session readable from the session, as LockConnIDVariable, after the lock query succeeds.
+	// The variable is NULL once the session does not hold any lock anymore.
+	ReportConnID bool
+
+	// LockWaitTimeout is set as @@lock_wait_timeout of the lock connection before the locks are
+	// acquired, so that the session variable the client expects is honored through vtgate.
+	// It is not set when zero.
+	LockWaitTimeout time.Duration
+
+	// LockFuncs are the locking functions of the query. Their results are used
+	// to keep track of the advisory locks held by the session.
+	LockFuncs []LockFunc
+
+	// fieldsOnly is set on the copy of the primitive used by GetFields. Its query
+	// does not evaluate the locking functions, so no lock is acquired or released.
+	fieldsOnly bool
+
 	noInputs
 
 	noTxNeeded
 }
 
+// LockFuncType is the type of a locking function.
+type LockFuncType int
+
+// This is the list of LockFuncType values.
+const (
+	GetLock = LockFuncType(iota)
+	IsFreeLock
+	IsUsedLock
+	ReleaseAllLocks
+	ReleaseLock
+)
+
+var lockFuncName = map[LockFuncType]string{
+	GetLock:         "get_lock",
+	IsFreeLock:      "is_free_lock",
+	IsUsedLock:      "is_used_lock",
+	ReleaseAllLocks: "release_all_locks",
+	ReleaseLock:     "release_lock",
+}
+
+func (t LockFuncType) String() string {
+	return lockFuncName[t]
+}
+
+// resultType returns the type MySQL uses for the result of the locking function.
+func (t LockFuncType) resultType() querypb.Type {
+	if t == IsUsedLock {
+		// IS_USED_LOCK returns the connection id of the lock holder.
+		return sqltypes.Uint64
+	}
+	return sqltypes.Int64
+}
+
+// LockFunc is a locking function selected by the lock query.
+type LockFunc struct {
+	Type LockFuncType
+	// Name is the name of the lock. It is not set for RELEASE_ALL_LOCKS.
+	Name sqltypes.PlanValue
+	// Column is the column of the result holding the value returned by the function.
+	Column int
+	// ColumnName is the name MySQL gives to the column, derived from the select expression.
+	ColumnName string
+}
+
+// LockOption configures the Lock primitive created by NewLock.
+type LockOption func(*Lock)
+
+// WithTimeout sets the timeout of the lock query.
+func WithTimeout(timeout time.Duration) LockOption {
+	return func(l *Lock) {
+		l.Timeout = timeout
+	}
+}
+
+// WithPrimaryOnly makes the lock fail when the destination is not a primary tablet.
+func WithPrimaryOnly() LockOption {
+	return func(l *Lock) {
+		l.PrimaryOnly = true
+	}
+}
+
+// WithRetry sets the number of times a failed lock query is retried.
+func WithRetry(retries int) LockOption {
+	return func(l *Lock) {
+		l.Retries = retries
+	}
+}
+
+// WithTableName sets the table reported by GetTableName, for lock queries
+// that are not written against dual.
+func WithTableName(name string) LockOption {
+	return func(l *Lock) {
+		l.TableName = name
+	}
+}
+
+// WithColumnAlias sets the name of the column of the lock result, for clients
+// expecting the alias they gave to the locking function.
+func WithColumnAlias(alias string) LockOption {
+	return func(l *Lock) {
+		l.ColumnAlias = alias
+	}
+}
+
+// WithVindex makes the lock query routed using the keyspace id the vindex maps the value to,
+// instead of a target destination.
+func WithVindex(vindex vindexes.SingleColumn, value sqltypes.PlanValue) LockOption {
+	return func(l *Lock) {
+		l.Vindex = vindex
+		l.Values = []sqltypes.PlanValue{value}
+	}
+}
+
+// WithBreaker makes the acquisitions of the lock go through the circuit breaker.
+func WithBreaker(breaker *LockBreaker) LockOption {
+	return func(l *Lock) {
+		l.Breaker = breaker
+	}
+}
+
+// WithFairQueue makes the acquisitions of the lock queue up in arrival order.
+func WithFairQueue() LockOption {
+	return func(l *Lock) {
+		l.Fair = true
+	}
+}
+
+// WithNamespace makes the lock names of the query prefixed with the namespace.
+func WithNamespace(namespace string) LockOption {
+	return func(l *Lock) {
+		l.Namespace = namespace
+	}
+}
+
+// WithReportConnID makes the id of the connection holding the locks readable from the session.
+func WithReportConnID() LockOption {
+	return func(l *Lock) {
+		l.ReportConnID = true
+	}
+}
+
+// WithLockWaitTimeout makes the lock connection use the timeout as @@lock_wait_timeout before acquiring the locks.
+func WithLockWaitTimeout(timeout time.Duration) LockOption {
+	return func(l *Lock) {
+		l.LockWaitTimeout = timeout
+	}
+}
+
+// WithLockFuncs sets the locking functions used to track the advisory locks of the session.
+func WithLockFuncs(funcs ...LockFunc) LockOption {
+	return func(l *Lock) {
+		l.LockFuncs = funcs
+	}
+}
+
+// NewLock creates a Lock primitive that sends the query to the given destination of the keyspace.
+// By default the lock query has no timeout, can be sent to any tablet type and is not retried.
+func NewLock(keyspace *vindexes.Keyspace, dest key.Destination, query string, opts ...LockOption) (*Lock, error) {
+	if keyspace == nil {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "lock primitive requires a keyspace")
+	}
+	if query == "" {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "lock primitive requires a query")
+	}
+	l := &Lock{
+		Keyspace:          keyspace,
+		TargetDestination: dest,
+		Query:             query,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if dest == nil && l.Vindex == nil {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "lock primitive requires a target destination")
+	}
+	if dest != nil && l.Vindex != nil {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "lock primitive cannot have both a target destination and a vindex")
+	}
+	if l.Timeout < 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid lock timeout: %v", l.Timeout)
+	}
+	if l.Retries < 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid lock retries: %d", l.Retries)
+	}
+	if l.Namespace != "" {
+		if err := l.applyNamespace(); err != nil {
+			return nil, err
+		}
+	}
+	if l.Timeout != 0 {
+		aligned, err := alignLockTimeout(l.Query, l.Timeout)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "invalid lock query")
+		}
+		l.Query = aligned
+	}
+	return l, nil
+}
+
+// alignLockTimeout rewrites the GET_LOCK calls of the query to wait for the given timeout,
+// so that the literal SQL does not drift from the timeout the planner intended.
+// The query is returned unchanged when its calls already use the timeout.
+func alignLockTimeout(query string, timeout time.Duration) (string, error) {
+	literal, secs := lockTimeoutArg(timeout)
+	return rewriteGetLocks(query, func(fn *sqlparser.FuncExpr) bool {
+		if len(fn.Exprs) == 0 {
+			return false
+		}
+		if len(fn.Exprs) == 1 {
+			fn.Exprs = append(fn.Exprs, &sqlparser.AliasedExpr{Expr: literal})
+			return true
+		}
+		if sqlparser.String(fn.Exprs[1]) == secs {
+			return false
+		}
+		fn.Exprs[1] = &sqlparser.AliasedExpr{Expr: literal}
+		return true
+	})
+}
+
+// clampLockTimeout rewrites the GET_LOCK calls of the query waiting longer than max, or forever,
+// to wait for max. The calls with a shorter timeout, or with a timeout that is not a literal,
+// are left unchanged: the deadline of the lock query bounds the latter.
+func clampLockTimeout(query string, max time.Duration) (string, error) {
+	literal, _ := lockTimeoutArg(max)
+	return rewriteGetLocks(query, func(fn *sqlparser.FuncExpr) bool {
+		if len(fn.Exprs) != 2 {
+			return false
+		}
+		timeout, ok := lockTimeoutLiteral(fn.Exprs[1])
+		if !ok || (timeout >= 0 && timeout <= max.Seconds()) {
+			return false
+		}
+		fn.Exprs[1] = &sqlparser.AliasedExpr{Expr: literal}
+		return true
+	})
+}
+
+// lockTimeoutArg returns the literal of the timeout argument of GET_LOCK waiting for the given
+// timeout, along with its text. MySQL expects the timeout in seconds.
+func lockTimeoutArg(timeout time.Duration) (*sqlparser.Literal, string) {
+	secs := strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64)
+	literal := sqlparser.NewIntLiteral([]byte(secs))
+	if _, err := strconv.ParseInt(secs, 10, 64); err != nil {
+		literal = sqlparser.NewFloatLiteral([]byte(secs))
+	}
+	return literal, secs
+}
+
+// rewriteGetLocks calls rewrite on the GET_LOCK calls of the query, which reports whether
+// it changed the call. The query is returned unchanged when no call was.
+func rewriteGetLocks(query string, rewrite func(fn *sqlparser.FuncExpr) bool) (string, error) {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return "", err
+	}
+	changed := false
+	sqlparser.Rewrite(stmt, func(cursor *sqlparser.Cursor) bool {
+		if fn, ok := cursor.Node().(*sqlparser.FuncExpr); ok && fn.Name.Lowered() == "get_lock" && rewrite(fn) {
+			changed = true
+		}
+		return true
+	}, nil)
+	if !changed {
+		return query, nil
+	}
+	return sqlparser.String(stmt), nil
+}
+
+// lockTimeoutLiteral returns the value of the timeout argument of GET_LOCK, if it is a literal.
+func lockTimeoutLiteral(expr sqlparser.SelectExpr) (float64, bool) {
+	aliased, ok := expr.(*sqlparser.AliasedExpr)
+	if !ok {
+		return 0, false
+	}
+	switch e := aliased.Expr.(type) {
+	case *sqlparser.Literal:
+		if e.Type != sqlparser.IntVal && e.Type != sqlparser.FloatVal {
+			return 0, false
+		}
+		timeout, err := strconv.ParseFloat(string(e.Val), 64)
+		return timeout, err == nil
+	case *sqlparser.UnaryExpr:
+		// negative timeouts wait forever.
+		if e.Operator != sqlparser.UMinusOp {
+			return 0, false
+		}
+		timeout, ok := lockTimeoutLiteral(&sqlparser.AliasedExpr{Expr: e.Expr})
+		return -timeout, ok
+	}
+	return 0, false
+}
+
+// withMaxLockWait returns the Lock primitive with its wait bounded by max.
+// The original Lock is returned when it already waits less than max.
+func (l *Lock) withMaxLockWait(max time.Duration) *Lock {
+	query := l.Query
+	if clamped, err := clampLockTimeout(query, max); err == nil {
+		query = clamped
+	}
+	if query == l.Query && l.Timeout != 0 && l.Timeout <= max {
+		return l
+	}
+	clone := l.Clone()
+	clone.Query = query
+	if clone.Timeout == 0 || clone.Timeout > max {
+		clone.Timeout = max
+	}
+	return clone
+}
+
+// Clone returns a copy of the Lock primitive.
+// The keyspace is copied as well, so modifying the clone never affects the original plan.
+func (l *Lock) Clone() *Lock {
+	clone := *l
+	clone.LockFuncs = append([]LockFunc(nil), l.LockFuncs...)
+	clone.Values = append([]sqltypes.PlanValue(nil), l.Values...)
+	if l.Keyspace != nil {
+		ks := *l.Keyspace
+		clone.Keyspace = &ks
+	}
+	return &clone
+}
+
+// WithTimeout returns a copy of the Lock primitive using the given timeout.
+// The original Lock is not modified.
+func (l *Lock) WithTimeout(timeout time.Duration) *Lock {
+	clone := l.Clone()
+	clone.Timeout = timeout
+	if timeout != 0 {
+		// the query of the original Lock was accepted by NewLock, so it parses.
+		if aligned, err := alignLockTimeout(clone.Query, timeout); err == nil {
+			clone.Query = aligned
+		}
+	}
+	return clone
+}
+
 // RouteType is part of the Primitive interface
-func (l *Lock) RouteType() string {
-	return "lock"
+func (l *Lock) RouteType() RouteType {
+	return RouteTypeLock
 }
 
 // GetKeyspaceName is part of the Primitive interface
@@ -55,44 +449,532 @@ func (l *Lock) GetKeyspaceName() string {
 
 // GetTableName is part of the Primitive interface
 func (l *Lock) GetTableName() string {
-	return "dual"
+	if l.TableName == "" {
+		return "dual"
+	}
+	return l.TableName
+}
+
+// HasSideEffects is part of the Primitive interface.
+// It acquires or releases advisory locks.
+func (l *Lock) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory is part of the Primitive interface.
+func (l *Lock) EstimatedMemory() int64 {
+	return primitiveMemory
 }
 
 // Execute is part of the Primitive interface
 func (l *Lock) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
-	rss, _, err := vcursor.ResolveDestinations(l.Keyspace.Name, nil, []key.Destination{l.TargetDestination})
+	return l.execute(vcursor, bindVars, nil)
+}
+
+// ExecuteOnShards executes the lock query on a shard resolved by the caller, e.g. once for
+// a batch of lock queries, instead of resolving the destination of the primitive again.
+// Exactly one shard must be given.
+func (l *Lock) ExecuteOnShards(vcursor VCursor, rss []*srvtopo.ResolvedShard, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	if len(rss) != 1 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "lock query must be sent to exactly one shard, got: %d", len(rss))
+	}
+	return l.execute(vcursor, bindVars, rss[0])
+}
+
+// execute executes the lock query on rs, or on the shard of the destination if rs is nil.
+func (l *Lock) execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, rs *srvtopo.ResolvedShard) (*sqltypes.Result, error) {
+	if !vcursor.AdvisoryLocksEnabled() {
+		// the plan could have been cached before the locks were disabled.
+		return nil, vterrors.New(vtrpc.Code_UNIMPLEMENTED, "advisory locks are disabled")
+	}
+	if l.fieldsOnly {
+		return l.executeFields(vcursor, bindVars)
+	}
+	if l.Keyspace.AdvisoryLocksDenied {
+		return nil, vterrors.Errorf(vtrpc.Code_PERMISSION_DENIED, "advisory locks are not allowed in keyspace %s", l.Keyspace.Name)
+	}
+	if !vcursor.ReservedConnEnabled() {
+		// the lock is held by the reserved connection of the session on the tablet.
+		return nil, vterrors.New(vtrpc.Code_FAILED_PRECONDITION, "advisory locks require reserved connections, which are disabled")
+	}
+	if max := vcursor.MaxLockWait(); max > 0 {
+		l = l.withMaxLockWait(max)
+	}
+	names, err := l.resolveLockNames(bindVars)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Timeout != 0 {
+		// the deadline only applies to the lock query, the later queries of the plan do not inherit it.
+		ctx, cancel := context.WithTimeout(vcursor.Context(), l.Timeout)
+		defer cancel()
+		restore := vcursor.SetContext(ctx)
+		defer restore()
+	}
+
+	if rs == nil {
+		if rs, err = l.resolveShard(vcursor, bindVars); err != nil {
+			return nil, err
+		}
+	}
+	if l.PrimaryOnly && rs.Target.TabletType != topodatapb.TabletType_MASTER {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "lock query can only be sent to a primary tablet, got: %v", rs.Target.TabletType)
+	}
+	if l.PrimaryOnly && vcursor.TargetReadOnly(rs.Target) {
+		// a lock acquired on a primary that is being demoted is lost with the failover.
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "lock query cannot be sent to the read-only primary tablet of %s/%s", rs.Target.Keyspace, rs.Target.Shard)
+	}
+
+	if vcursor.DryRun() {
+		// the lock query would be routed: only the limit of the session remains to be checked.
+		if err := checkMaxAdvisoryLocks(vcursor, l.getLockNames(names)); err != nil {
+			return nil, err
+		}
+		return &sqltypes.Result{}, nil
+	}
+
+	breakerLock, hasBreaker := l.breakerLock(names)
+	if hasBreaker && !l.Breaker.allow(breakerLock) {
+		return l.lockUnavailable(vcursor, names)
+	}
+
+	acquired := l.getLockNames(names)
+	if len(acquired) != 0 {
+		if err := checkMaxAdvisoryLocks(vcursor, acquired); err != nil {
+			return nil, err
+		}
+		session := vcursor.Session().SessionKey()
+		if err := lockWaits.wait(session, rs.Target, acquired); err != nil {
+			return nil, err
+		}
+		defer lockWaits.done(session)
+		defer lockContention.startWait(acquired)()
+		if l.Fair {
+			if queued := queuedLocks(session, rs.Target, acquired); len(queued) != 0 {
+				leave, err := lockQueues.enter(vcursor.Context(), queued)
+				if err != nil {
+					return nil, err
+				}
+				defer leave()
+			}
+		}
+	}
+
+	if len(acquired) != 0 && l.LockWaitTimeout > 0 {
+		if err := vcursor.SetLockWaitTimeout(rs, l.LockWaitTimeout); err != nil {
+			return nil, reservedPoolExhausted(err)
+		}
+	}
+
+	var qr *sqltypes.Result
+	for attempt := 0; ; attempt++ {
+		// the vcursor can modify the bound query, so each attempt sets it again.
+		query := boundQueries.Get().(*querypb.BoundQuery)
+		query.Sql = l.Query
+		query.BindVariables = bindVars
+		qr, err = vcursor.ExecuteLock(rs, query)
+		query.Reset()
+		boundQueries.Put(query)
+		if err == nil || attempt >= l.Retries || ClassifyLockError(err) != LockErrorRetryable {
+			break
+		}
+	}
+	if hasBreaker {
+		switch {
+		case l.lockBusy(qr, err):
+			l.Breaker.record(breakerLock, true)
+		case err == nil:
+			l.Breaker.record(breakerLock, false)
+		}
+	}
+	l.audit(vcursor, names, qr, err)
+	l.notifyLockEvents(vcursor, names, qr, err)
+	if err != nil {
+		return nil, reservedPoolExhausted(err)
+	}
+	l.trackLocks(vcursor, names, qr)
+	if l.ReportConnID {
+		l.reportConnID(vcursor)
+	}
+	if vcursor.InTransaction() {
+		l.warnInTransaction(vcursor, names, qr)
+	}
+	return l.typeLockFuncs(qr)
+}
+
+// boundQueries recycles the bound queries of the lock queries, which are not retained
+// once VCursor.ExecuteLock returns, to save an allocation per lock query.
+var boundQueries = sync.Pool{
+	New: func() interface{} {
+		return &querypb.BoundQuery{}
+	},
+}
+
+// queuedLocks returns the locks a fair acquisition queues up for: the ones the session does not hold yet.
+// Acquiring a lock the session holds again returns right away, so it must not wait behind the sessions
+// waiting for it to be released.
+func queuedLocks(session interface{}, target *querypb.Target, names []string) []lockKey {
+	var queued []lockKey
+	for _, name := range names {
+		if _, held := lockWaits.acquiredAt(session, target, name); !held {
+			queued = append(queued, newLockKey(target, name))
+		}
+	}
+	return queued
+}
+
+// warnInTransaction records a warning for every lock acquired while a transaction is open.
+// Advisory locks are not part of the transaction: unless release_locks_on_commit is set,
+// they are still held after the transaction is committed or rolled back.
+func (l *Lock) warnInTransaction(vcursor VCursor, names []string, qr *sqltypes.Result) {
+	if len(qr.Rows) != 1 {
+		return
+	}
+	for i, lf := range l.LockFuncs {
+		if lf.Type != GetLock || lf.Column >= len(qr.Rows[0]) || qr.Rows[0][lf.Column].ToString() != "1" {
+			continue
+		}
+		vcursor.Session().RecordWarning(&querypb.QueryWarning{
+			Message: fmt.Sprintf("advisory lock '%s' acquired in a transaction is not released when the transaction ends", names[i]),
+		})
+	}
+}
+
+// fieldQuery returns a copy of the primitive in fields-only mode: its query selects
+// the same columns, but never returns a row, so the locking functions are not evaluated.
+func (l *Lock) fieldQuery() (*Lock, error) {
+	stmt, err := sqlparser.Parse(l.Query)
+	if err != nil {
+		return nil, err
+	}
+	buf := sqlparser.NewTrackedBuffer(sqlparser.FormatImpossibleQuery)
+	buf.Myprintf("%v", stmt)
+	clone := l.Clone()
+	clone.Query = buf.String()
+	clone.Retries = 0
+	clone.fieldsOnly = true
+	return clone, nil
+}
+
+// executeFields sends the query of the primitive in fields-only mode. No lock is acquired, so
+// the lock names are not resolved: they are NULL placeholders when a statement is prepared.
+// The query is sent outside of the lock connection, to the destination of the primitive:
+// the fields are the same on every shard.
+func (l *Lock) executeFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	dest, err := l.destination(vcursor)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := resolveSingleDestination(vcursor, l.Keyspace.Name, dest, "lock")
+	if err != nil {
+		return nil, err
+	}
+	qr, err := vcursor.ExecuteStandalone(l.Query, bindVars, rs)
+	if err != nil {
+		return nil, err
+	}
+	return l.typeLockFuncs(qr)
+}
+
+// resolveShard returns the shard the lock query is sent to.
+func (l *Lock) resolveShard(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*srvtopo.ResolvedShard, error) {
+	if l.Vindex == nil {
+		dest, err := l.destination(vcursor)
+		if err != nil {
+			return nil, err
+		}
+		return resolveSingleDestination(vcursor, l.Keyspace.Name, dest, "lock")
+	}
+	// the lock of a logical key must be acquired on the same shard by every session,
+	// so the vindex takes precedence over the destination the session is pinned to.
+	if len(l.Values) != 1 {
+		return nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "lock primitive requires a single vindex value, got: %d", len(l.Values))
+	}
+	value, err := l.Values[0].ResolveValue(bindVars)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "invalid lock vindex value")
+	}
+	rss, _, err := resolveShards(vcursor, l.Vindex, l.Keyspace, []sqltypes.Value{value})
 	if err != nil {
 		return nil, err
 	}
 	if len(rss) != 1 {
-		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "lock query cannot be routed to vttablet: %v", rss)
+		return nil, routingError(vtrpc.Code_FAILED_PRECONDITION, l.Keyspace.Name, nil,
+			fmt.Sprintf("lock query cannot be routed to vttablet: vindex %s maps %v to shards %v", l.Vindex.String(), value, shardNames(rss)))
+	}
+	return rss[0], nil
+}
+
+// destination returns the destination of the lock query. When the session is pinned
+// to a destination by its target string, the lock follows it, so that the lock is
+// held where the other queries of the session go. Only an explicit shard of the
+// primitive can conflict with the session destination.
+func (l *Lock) destination(vcursor VCursor) (key.Destination, error) {
+	keyspace, dest := vcursor.SessionDestination()
+	if dest == nil {
+		return l.TargetDestination, nil
+	}
+	if keyspace != l.Keyspace.Name {
+		return nil, routingError(vtrpc.Code_INVALID_ARGUMENT, l.Keyspace.Name, l.TargetDestination,
+			fmt.Sprintf("lock keyspace conflicts with the session target keyspace %s", keyspace))
+	}
+	if shard, ok := l.TargetDestination.(key.DestinationShard); ok && shard.String() != dest.String() {
+		return nil, routingError(vtrpc.Code_INVALID_ARGUMENT, l.Keyspace.Name, shard,
+			fmt.Sprintf("lock destination conflicts with the session target destination %v", dest))
+	}
+	return dest, nil
+}
+
+// typeLockFuncs returns the result with the columns of the locking functions using
+// the type and name MySQL would return, whatever the tablet answered with.
+// Typed drivers and prepared statements rely on them. The column is named ColumnAlias when it is set.
+func (l *Lock) typeLockFuncs(qr *sqltypes.Result) (*sqltypes.Result, error) {
+	if len(l.LockFuncs) == 0 && l.ColumnAlias == "" {
+		return qr, nil
+	}
+	qr = qr.Copy()
+	for _, lf := range l.LockFuncs {
+		typ := lf.Type.resultType()
+		if lf.Column < len(qr.Fields) {
+			qr.Fields[lf.Column].Type = typ
+			if lf.ColumnName != "" {
+				qr.Fields[lf.Column].Name = lf.ColumnName
+			}
+		}
+		for _, row := range qr.Rows {
+			if lf.Column >= len(row) || row[lf.Column].IsNull() {
+				continue
+			}
+			val, err := sqltypes.NewValue(typ, row[lf.Column].ToBytes())
+			if err != nil {
+				return nil, err
+			}
+			row[lf.Column] = val
+		}
+	}
+	if l.ColumnAlias != "" {
+		column := 0
+		if len(l.LockFuncs) > 0 {
+			column = l.LockFuncs[0].Column
+		}
+		if column < len(qr.Fields) {
+			qr.Fields[column].Name = l.ColumnAlias
+		}
+	}
+	return qr, nil
+}
+
+// resolveLockNames returns the name of the lock of each locking function, including its
+// namespace, or an empty string for RELEASE_ALL_LOCKS. The names are validated like MySQL does, so that invalid
+// names arriving as bind variables are rejected before the query is sent to vttablet.
+func (l *Lock) resolveLockNames(bindVars map[string]*querypb.BindVariable) ([]string, error) {
+	names := make([]string, len(l.LockFuncs))
+	for i, lf := range l.LockFuncs {
+		if lf.Type == ReleaseAllLocks {
+			continue
+		}
+		if lf.Name.Key != "" {
+			bv, ok := bindVars[lf.Name.Key]
+			if !ok {
+				return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "missing bind var %s for the lock name", lf.Name.Key)
+			}
+			// vttablet would only fail later, with an error not naming the bind variable.
+			if !isLockNameType(bv.Type) {
+				return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "bind var %s of type %v cannot be used as a lock name", lf.Name.Key, bv.Type)
+			}
+		}
+		name, err := lf.Name.ResolveValue(bindVars)
+		if err != nil {
+			return nil, vterrors.Wrap(err, "invalid lock name")
+		}
+		if name.IsNull() {
+			return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "incorrect user-level lock name: NULL")
+		}
+		if n := utf8.RuneCountInString(name.ToString()); n == 0 || n > l.maxNameLength() {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "incorrect user-level lock name '%s': it must have between 1 and %d characters", name.ToString(), l.maxNameLength())
+		}
+		names[i] = l.namespaced(name.ToString())
 	}
+	return names, nil
+}
+
+// isLockNameType returns true if a bind variable of the type can be used as a lock name:
+// MySQL converts it to a string. NULL names are rejected with the error MySQL returns.
+func isLockNameType(typ querypb.Type) bool {
+	return sqltypes.IsText(typ) || sqltypes.IsIntegral(typ) || typ == sqltypes.VarBinary || typ == sqltypes.Binary || typ == sqltypes.Null
+}
 
-	query := &querypb.BoundQuery{
-		Sql:           l.Query,
-		BindVariables: bindVars,
+// getLockNames returns the names of the locks acquired by the GET_LOCK functions of the query.
+func (l *Lock) getLockNames(names []string) []string {
+	var acquired []string
+	for i, lf := range l.LockFuncs {
+		if lf.Type == GetLock {
+			acquired = append(acquired, names[i])
+		}
 	}
-	return vcursor.ExecuteLock(rss[0], query)
+	return acquired
+}
+
+// checkMaxAdvisoryLocks fails if acquiring the locks would make the session hold more
+// advisory locks than allowed. Locks already held by the session do not count, since
+// acquiring them again only increments their count.
+func checkMaxAdvisoryLocks(vcursor VCursor, names []string) error {
+	max := vcursor.MaxAdvisoryLocks()
+	if max <= 0 {
+		return nil
+	}
+	session := vcursor.Session()
+	held := session.AdvisoryLocksHeld()
+	added := make(map[string]bool)
+	for _, name := range names {
+		if session.AdvisoryLockCount(name) == 0 {
+			added[name] = true
+		}
+	}
+	if held+len(added) > max {
+		return vterrors.Errorf(vtrpc.Code_RESOURCE_EXHAUSTED, "too many advisory locks held by the session: the maximum is %d", max)
+	}
+	return nil
+}
+
+// trackLocks updates the advisory locks held by the session from the results of the locking functions.
+// Like MySQL, a lock acquired several times by the same session must be released as many times.
+func (l *Lock) trackLocks(vcursor VCursor, names []string, qr *sqltypes.Result) {
+	if len(l.LockFuncs) == 0 || len(qr.Rows) != 1 {
+		return
+	}
+	session := vcursor.Session()
+	// the lock query was sent: the session has a lock connection from now on.
+	key, target := session.SessionKey(), session.LockSessionTarget()
+	heldBefore := session.AdvisoryLocksHeld()
+	defer func() {
+		held := session.AdvisoryLocksHeld()
+		switch {
+		case heldBefore == 0 && held > 0:
+			lockHolds.started(key)
+		case heldBefore > 0 && held == 0:
+			lockHolds.ended(key, "Released")
+		}
+	}()
+	row := qr.Rows[0]
+	for i, lf := range l.LockFuncs {
+		if lf.Column >= len(row) {
+			continue
+		}
+		switch lf.Type {
+		case ReleaseAllLocks:
+			session.ResetAdvisoryLocks()
+			lockWaits.releasedAll(key)
+		case GetLock, ReleaseLock:
+			name := names[i]
+			count := session.AdvisoryLockCount(name)
+			succeeded := row[lf.Column].ToString() == "1"
+			switch {
+			case lf.Type == GetLock && succeeded:
+				count++
+			case lf.Type == ReleaseLock && succeeded:
+				count--
+			case lf.Type == ReleaseLock && count > 0:
+				// the lock is not held anymore, e.g. because the lock connection was lost.
+				count = 0
+			default:
+				continue
+			}
+			session.SetAdvisoryLockCount(name, count)
+			if count > 0 {
+				lockWaits.acquired(key, target, name)
+			} else {
+				lockWaits.released(key, target, name)
+			}
+		}
+	}
+}
+
+// reportConnID sets LockConnIDVariable to the id of the reserved connection holding
+// the locks of the session, or to NULL when the session does not hold any lock.
+func (l *Lock) reportConnID(vcursor VCursor) {
+	var id interface{}
+	if vcursor.Session().AdvisoryLocksHeld() > 0 {
+		id = vcursor.ReservedConnID()
+	}
+	// an int64 or nil is always a valid bind variable.
+	_ = vcursor.Session().SetUDV(LockConnIDVariable, id)
 }
 
 // StreamExecute is part of the Primitive interface
+// Like a streaming query on the tablet, the fields are sent first, if wanted, then the row.
 func (l *Lock) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
 	qr, err := l.Execute(vcursor, bindVars, wantfields)
 	if err != nil {
-		return err
+		return vterrors.Wrap(err, "Lock.StreamExecute")
+	}
+	if wantfields {
+		if err := callback(&sqltypes.Result{Fields: qr.Fields}); err != nil {
+			return vterrors.Wrap(err, "Lock.StreamExecute")
+		}
 	}
-	return callback(qr)
+	return vterrors.Wrap(callback(&sqltypes.Result{Rows: qr.Rows}), "Lock.StreamExecute")
 }
 
 // GetFields is part of the Primitive interface
 func (l *Lock) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
-	return nil, vterrors.New(vtrpc.Code_UNIMPLEMENTED, "not implements in lock primitive")
+	fl, err := l.fieldQuery()
+	if err != nil {
+		return nil, err
+	}
+	return fieldsFromExecute(fl, vcursor, bindVars)
+}
+
+// String returns a printable version of the primitive, with the literals of the query redacted.
+func (l *Lock) String() string {
+	return primitiveString("Lock", l.Keyspace, l.TargetDestination, l.Query)
+}
+
+// Cost implements the Coster interface.
+// A lock query is always sent to a single shard.
+func (l *Lock) Cost() int {
+	return singleShardCost
 }
 
 func (l *Lock) description() PrimitiveDescription {
 	other := map[string]interface{}{
 		"Query": l.Query,
 	}
+	if l.Timeout != 0 {
+		other["Timeout"] = l.Timeout.String()
+	}
+	if l.PrimaryOnly {
+		other["PrimaryOnly"] = true
+	}
+	if l.Retries != 0 {
+		other["Retries"] = l.Retries
+	}
+	if tableName := l.GetTableName(); tableName != "dual" {
+		other["TableName"] = tableName
+	}
+	if l.ColumnAlias != "" {
+		other["ColumnAlias"] = l.ColumnAlias
+	}
+	if l.Vindex != nil {
+		other["Vindex"] = l.Vindex.String()
+		other["Values"] = l.Values
+	}
+	if l.Fair {
+		other["Fair"] = true
+	}
+	if l.Namespace != "" {
+		other["Namespace"] = l.Namespace
+	}
+	if l.ReportConnID {
+		other["ReportConnID"] = true
+	}
+	if l.LockWaitTimeout != 0 {
+		other["LockWaitTimeout"] = l.LockWaitTimeout.String()
+	}
+	if l.Breaker != nil {
+		other["Breaker"] = fmt.Sprintf("%d in %v, cooldown %v", l.Breaker.Threshold, l.Breaker.Window, l.Breaker.Cooldown)
+	}
 	return PrimitiveDescription{
 		OperatorType:      "Lock",
 		Keyspace:          l.Keyspace,
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/callerid"
+)
+
+// LockAuditLogger receives an audit record for every advisory lock operation.
+// It is separate from the query log, so the lock activity can be shipped on its own.
+var LockAuditLogger = streamlog.New("LockAudit", 10)
+
+// LockAuditRecord is the audit record of an advisory lock operation.
+type LockAuditRecord struct {
+	// Principal is the effective caller, User is the immediate caller.
+	Principal   string `json:"principal"`
+	User        string `json:"user"`
+	Action      string `json:"action"`
+	LockName    string `json:"lock_name,omitempty"`
+	Keyspace    string `json:"keyspace"`
+	Destination string `json:"destination"`
+	// ReservedConnID is the id of the reserved connection holding the locks of the session, if any.
+	ReservedConnID int64 `json:"reserved_conn_id,omitempty"`
+	// Outcome is the value returned by the locking function, or the error of the lock query.
+	Outcome string `json:"outcome"`
+}
+
+// Logf formats the record as a line of JSON.
+// It implements the streamlog.Formatter interface.
+func (r *LockAuditRecord) Logf(w io.Writer, _ url.Values) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// audit sends one audit record per locking function of the query.
+func (l *Lock) audit(vcursor VCursor, names []string, qr *sqltypes.Result, err error) {
+	if len(l.LockFuncs) == 0 {
+		return
+	}
+	ctx := vcursor.Context()
+	principal := callerid.GetPrincipal(callerid.EffectiveCallerIDFromContext(ctx))
+	user := callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx))
+	destination := ""
+	if l.TargetDestination != nil {
+		// the destination is not set when the lock is routed through a vindex.
+		destination = l.TargetDestination.String()
+	}
+	reservedConnID := vcursor.ReservedConnID()
+	for i, lf := range l.LockFuncs {
+		record := &LockAuditRecord{
+			Principal:      principal,
+			User:           user,
+			Action:         lf.Type.String(),
+			LockName:       names[i],
+			Keyspace:       l.Keyspace.Name,
+			Destination:    destination,
+			ReservedConnID: reservedConnID,
+		}
+		if err != nil {
+			record.Outcome = "error: " + err.Error()
+		} else {
+			record.Outcome = lf.outcome(qr)
+		}
+		LockAuditLogger.Send(record)
+	}
+}
+
+// outcome returns the value returned by the locking function, "NULL" for NULL,
+// or an empty string if the result does not have it.
+func (lf LockFunc) outcome(qr *sqltypes.Result) string {
+	if len(qr.Rows) != 1 || lf.Column >= len(qr.Rows[0]) {
+		return ""
+	}
+	if v := qr.Rows[0][lf.Column]; !v.IsNull() {
+		return v.ToString()
+	}
+	return "NULL"
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// newBenchLock returns a Lock acquiring a lock, and a cursor answering it is acquired.
+// The cursor is rewound before every call, so that its log does not grow.
+func newBenchLock(b *testing.B) (*Lock, *loggingVCursor) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}}))
+	if err != nil {
+		b.Fatal(err)
+	}
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1")
+	return l, &loggingVCursor{results: []*sqltypes.Result{acquired}}
+}
+
+func BenchmarkLockExecute(b *testing.B) {
+	l, vc := newBenchLock(b)
+	defer lockWaits.releasedAll(vc)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vc.Rewind()
+		if _, err := l.Execute(vc, nil, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLockStreamExecute(b *testing.B) {
+	l, vc := newBenchLock(b)
+	defer lockWaits.releasedAll(vc)
+	callback := func(*sqltypes.Result) error { return nil }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vc.Rewind()
+		if err := l.StreamExecute(vc, nil, true, callback); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLockGetFields(b *testing.B) {
+	l, vc := newBenchLock(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vc.Rewind()
+		if _, err := l.GetFields(vc, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
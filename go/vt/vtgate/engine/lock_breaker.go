@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// LockBreaker is a circuit breaker for the acquisition of advisory locks, keyed by lock name.
+// Once the acquisition of a lock is busy Threshold times in a row within Window, the breaker
+// of the lock opens: for Cooldown, GET_LOCK returns 0 right away, without using a reserved
+// connection to wait for a lock that is unlikely to be released.
+type LockBreaker struct {
+	Threshold int
+	Window    time.Duration
+	Cooldown  time.Duration
+
+	now func() time.Time
+
+	mu    sync.Mutex
+	locks map[string]*lockBreakerState
+}
+
+type lockBreakerState struct {
+	// busy is the number of busy acquisitions in a row since firstBusy.
+	busy      int
+	firstBusy time.Time
+	// openUntil is the end of the cooldown, if the breaker is open.
+	openUntil time.Time
+}
+
+// NewLockBreaker creates a LockBreaker.
+func NewLockBreaker(threshold int, window, cooldown time.Duration) *LockBreaker {
+	return &LockBreaker{
+		Threshold: threshold,
+		Window:    window,
+		Cooldown:  cooldown,
+		now:       time.Now,
+		locks:     make(map[string]*lockBreakerState),
+	}
+}
+
+// allow returns false if the breaker of the lock is open.
+func (b *LockBreaker) allow(name string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state, ok := b.locks[name]
+	if !ok || state.openUntil.IsZero() {
+		return true
+	}
+	if b.now().Before(state.openUntil) {
+		return false
+	}
+	// the cooldown is over: the breaker closes.
+	delete(b.locks, name)
+	return true
+}
+
+// record records the outcome of an acquisition of the lock.
+func (b *LockBreaker) record(name string, busy bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !busy {
+		delete(b.locks, name)
+		return
+	}
+	now := b.now()
+	state, ok := b.locks[name]
+	if !ok || now.Sub(state.firstBusy) > b.Window {
+		state = &lockBreakerState{firstBusy: now}
+		b.locks[name] = state
+	}
+	state.busy++
+	if state.busy >= b.Threshold {
+		state.openUntil = now.Add(b.Cooldown)
+	}
+}
+
+// breakerLock returns the name of the lock guarded by the breaker. Only the lock queries
+// selecting a single GET_LOCK are, so that the result of a skipped query can be built.
+func (l *Lock) breakerLock(names []string) (string, bool) {
+	if l.Breaker == nil || len(l.LockFuncs) != 1 || l.LockFuncs[0].Type != GetLock || l.LockFuncs[0].Column != 0 {
+		return "", false
+	}
+	return names[0], true
+}
+
+// lockUnavailable returns the result of a GET_LOCK that timed out, for a lock query skipped by the
+// breaker. The result is named and typed like the ones of the lock queries sent, and the skipped
+// acquisition is audited and reported as a lock event like them.
+func (l *Lock) lockUnavailable(vcursor VCursor, names []string) (*sqltypes.Result, error) {
+	qr := &sqltypes.Result{
+		Fields:       []*querypb.Field{{Name: GetLock.String(), Type: sqltypes.Int64}},
+		RowsAffected: 1,
+		Rows:         [][]sqltypes.Value{{sqltypes.NewInt64(0)}},
+	}
+	l.audit(vcursor, names, qr, nil)
+	l.notifyLockEvents(vcursor, names, qr, nil)
+	return l.typeLockFuncs(qr)
+}
+
+// lockBusy returns true if the lock query guarded by the breaker found the lock held by another session.
+func (l *Lock) lockBusy(qr *sqltypes.Result, err error) bool {
+	if err != nil {
+		if serr, ok := mysql.NewSQLErrorFromError(err).(*mysql.SQLError); ok && serr.Num == mysql.ERLockWaitTimeout {
+			return true
+		}
+		return vterrors.Code(err) == vtrpc.Code_DEADLINE_EXCEEDED
+	}
+	return l.LockFuncs[0].outcome(qr) == "0"
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// LockErrorCategory tells whether a failed lock query can be retried.
+type LockErrorCategory int
+
+// This is the list of LockErrorCategory values.
+const (
+	// LockErrorFatal errors must be returned to the client: retrying the lock query
+	// would fail the same way, or would wait for the lock again.
+	LockErrorFatal = LockErrorCategory(iota)
+	// LockErrorRetryable errors are connection or transient failures: the lock query
+	// did not run, or its result was lost, and it can be sent again.
+	LockErrorRetryable
+)
+
+var lockErrorCategoryName = map[LockErrorCategory]string{
+	LockErrorFatal:     "fatal",
+	LockErrorRetryable: "retryable",
+}
+
+func (c LockErrorCategory) String() string {
+	return lockErrorCategoryName[c]
+}
+
+// ClassifyLockError returns the category of an error returned by a lock query.
+// The error itself is left untouched, so its vtrpc code is preserved for the client.
+func ClassifyLockError(err error) LockErrorCategory {
+	if err == nil {
+		return LockErrorFatal
+	}
+	if serr, ok := mysql.NewSQLErrorFromError(err).(*mysql.SQLError); ok {
+		switch serr.Num {
+		case mysql.CRConnectionError, mysql.CRServerGone, mysql.CRServerLost:
+			return LockErrorRetryable
+		case mysql.ERLockWaitTimeout, mysql.ERLockDeadlock:
+			// the lock is busy.
+			return LockErrorFatal
+		}
+	}
+	switch vterrors.Code(err) {
+	case vtrpc.Code_UNAVAILABLE, vtrpc.Code_UNKNOWN:
+		// UNKNOWN is the code of the errors of the connection to vttablet.
+		return LockErrorRetryable
+	}
+	return LockErrorFatal
+}
+
+// reservedPoolMessages are the messages of the errors vttablet returns when it has no connection
+// left to reserve: reserved connections come from its transaction pool.
+var reservedPoolMessages = []string{
+	"transaction pool connection limit exceeded",
+	"transaction pool aborting request due to already expired context",
+}
+
+// reservedPoolExhausted returns an error telling how to remedy the exhaustion of the reserved
+// connections of vttablet, if it is the reason the lock query failed. Otherwise err is returned.
+func reservedPoolExhausted(err error) error {
+	if vterrors.Code(err) != vtrpc.Code_RESOURCE_EXHAUSTED {
+		return err
+	}
+	for _, msg := range reservedPoolMessages {
+		if strings.Contains(err.Error(), msg) {
+			return vterrors.Errorf(vtrpc.Code_RESOURCE_EXHAUSTED, "no connection left to reserve for the advisory locks (%v): increase the transaction pool of the tablets (queryserver-config-transaction-cap), or release the locks of idle sessions", err.Error())
+		}
+	}
+	return err
+}
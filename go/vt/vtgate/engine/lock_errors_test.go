@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestClassifyLockError(t *testing.T) {
+	tcases := []struct {
+		err  error
+		want LockErrorCategory
+	}{{
+		err:  errors.New("connection reset"),
+		want: LockErrorRetryable,
+	}, {
+		err:  vterrors.New(vtrpc.Code_UNAVAILABLE, "no healthy tablet"),
+		want: LockErrorRetryable,
+	}, {
+		err:  mysql.NewSQLError(mysql.CRServerGone, mysql.SSUnknownSQLState, "server has gone away"),
+		want: LockErrorRetryable,
+	}, {
+		err:  mysql.NewSQLError(mysql.CRServerLost, mysql.SSUnknownSQLState, "lost connection"),
+		want: LockErrorRetryable,
+	}, {
+		err:  mysql.NewSQLError(mysql.ERLockWaitTimeout, mysql.SSUnknownSQLState, "lock wait timeout exceeded"),
+		want: LockErrorFatal,
+	}, {
+		err:  mysql.NewSQLError(mysql.ERLockDeadlock, mysql.SSUnknownSQLState, "deadlock found"),
+		want: LockErrorFatal,
+	}, {
+		err:  vterrors.New(vtrpc.Code_DEADLINE_EXCEEDED, "lock timeout"),
+		want: LockErrorFatal,
+	}, {
+		err:  context.Canceled,
+		want: LockErrorFatal,
+	}, {
+		err:  vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "syntax error"),
+		want: LockErrorFatal,
+	}, {
+		err:  vterrors.New(vtrpc.Code_RESOURCE_EXHAUSTED, "too many locks"),
+		want: LockErrorFatal,
+	}, {
+		err:  nil,
+		want: LockErrorFatal,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.want.String(), func(t *testing.T) {
+			assert.Equal(t, tcase.want, ClassifyLockError(tcase.err), "%v", tcase.err)
+		})
+	}
+}
+
+func TestLockRetryFatalError(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual", WithRetry(1))
+	require.NoError(t, err)
+
+	vc := &loggingVCursor{
+		results:   []*sqltypes.Result{nil, nil},
+		resultErr: vterrors.New(vtrpc.Code_DEADLINE_EXCEEDED, "lock timeout"),
+	}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "lock timeout")
+	// the error code is preserved.
+	assert.Equal(t, vtrpc.Code_DEADLINE_EXCEEDED, vterrors.Code(err))
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select get_lock('lock name', 10) from dual {}",
+	})
+}
+
+func TestLockReservedPoolExhausted(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+
+	vc := &loggingVCursor{
+		results:   []*sqltypes.Result{nil},
+		resultErr: vterrors.New(vtrpc.Code_RESOURCE_EXHAUSTED, "transaction pool connection limit exceeded"),
+	}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "no connection left to reserve for the advisory locks (transaction pool connection limit exceeded): increase the transaction pool of the tablets (queryserver-config-transaction-cap), or release the locks of idle sessions")
+	assert.Equal(t, vtrpc.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+
+	// the other resource errors are returned as they are.
+	vc = &loggingVCursor{
+		results:   []*sqltypes.Result{nil},
+		resultErr: vterrors.New(vtrpc.Code_RESOURCE_EXHAUSTED, "query pool full"),
+	}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "query pool full")
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// LockEvent describes an advisory lock being acquired or released.
+type LockEvent struct {
+	// Type is GetLock, ReleaseLock or ReleaseAllLocks.
+	Type LockFuncType
+	// Name is the name of the lock. It is empty for ReleaseAllLocks.
+	Name string
+	// Outcome is the value returned by the locking function, like "1" or "NULL".
+	// It is empty when the lock query failed.
+	Outcome string
+	// Err is the error of the lock query, if any.
+	Err error
+}
+
+// LockEventHook is called by the VCursor for every LockEvent, once the lock query completes.
+// Embedding applications use it to react to the locks of their sessions.
+type LockEventHook func(ctx context.Context, event LockEvent)
+
+// notifyLockEvents reports the acquisitions and releases of the query to the VCursor.
+func (l *Lock) notifyLockEvents(vcursor VCursor, names []string, qr *sqltypes.Result, err error) {
+	for i, lf := range l.LockFuncs {
+		if lf.Type != GetLock && lf.Type != ReleaseLock && lf.Type != ReleaseAllLocks {
+			continue
+		}
+		event := LockEvent{Type: lf.Type, Name: names[i], Err: err}
+		if err == nil {
+			event.Outcome = lf.outcome(qr)
+		}
+		vcursor.OnLockEvent(event)
+	}
+}
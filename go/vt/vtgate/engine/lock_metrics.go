@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+)
+
+// LockMetricsNameBuckets is the number of buckets the lock names are hashed into
+// before being used as a label of the lock contention metrics. It bounds the
+// cardinality of the metrics. 0 means the lock names are used as they are.
+var LockMetricsNameBuckets = 0
+
+// lockContention maintains the lock contention metrics of the Lock primitives.
+var lockContention = &lockContentionMetrics{
+	now:       time.Now,
+	waiters:   stats.NewGaugesWithSingleLabel("LockWaiters", "Number of sessions waiting to acquire an advisory lock", "LockName"),
+	waitTimes: stats.NewTimings("LockWaitTimes", "Time spent waiting to acquire advisory locks", "LockName"),
+}
+
+type lockContentionMetrics struct {
+	now       func() time.Time
+	waiters   *stats.GaugesWithSingleLabel
+	waitTimes *stats.Timings
+}
+
+// startWait records that the session starts waiting for the locks.
+// The returned function must be called once the wait is over.
+func (m *lockContentionMetrics) startWait(names []string) func() {
+	labels := make([]string, len(names))
+	for i, name := range names {
+		labels[i] = lockMetricsLabel(name)
+		m.waiters.Add(labels[i], 1)
+	}
+	start := m.now()
+	return func() {
+		elapsed := m.now().Sub(start)
+		for _, label := range labels {
+			m.waiters.Add(label, -1)
+			m.waitTimes.Add(label, elapsed)
+		}
+	}
+}
+
+// lockHolds measures how long the sessions hold advisory locks, which is how long the reserved
+// connections holding them stay checked out. Long hold times point at leaked locks.
+var lockHolds = &lockHoldMetrics{
+	now:       time.Now,
+	since:     make(map[interface{}]time.Time),
+	holdTimes: stats.NewTimings("LockConnHoldTimes", "Time the reserved connections stay checked out because of advisory locks", "Reason"),
+}
+
+type lockHoldMetrics struct {
+	now       func() time.Time
+	holdTimes *stats.Timings
+
+	mu sync.Mutex
+	// since is the time each session started holding advisory locks, by VCursor.SessionKey.
+	since map[interface{}]time.Time
+}
+
+// started records that the session holds its first advisory lock.
+func (m *lockHoldMetrics) started(session interface{}) {
+	if session == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.since[session] = m.now()
+}
+
+// ended records that the session does not hold advisory locks anymore, for the given reason.
+func (m *lockHoldMetrics) ended(session interface{}, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	start, ok := m.since[session]
+	if !ok {
+		return
+	}
+	delete(m.since, session)
+	m.holdTimes.Add(reason, m.now().Sub(start))
+}
+
+// lockMetricsLabel returns the label used for the lock name in the metrics.
+func lockMetricsLabel(name string) string {
+	if LockMetricsNameBuckets <= 0 {
+		return name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("bucket%d", h.Sum32()%uint32(LockMetricsNameBuckets))
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"unicode/utf8"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// lockNamespaceSeparator separates the namespace from the lock name.
+const lockNamespaceSeparator = ":"
+
+// namespaced returns the name MySQL knows the lock by.
+func (l *Lock) namespaced(name string) string {
+	if l.Namespace == "" {
+		return name
+	}
+	return l.Namespace + lockNamespaceSeparator + name
+}
+
+// maxNameLength returns the maximum length of the lock names of the query:
+// MySQL limits the length of the name including the namespace.
+func (l *Lock) maxNameLength() int {
+	if l.Namespace == "" {
+		return maxLockNameLength
+	}
+	return maxLockNameLength - utf8.RuneCountInString(l.namespaced(""))
+}
+
+// applyNamespace rewrites the lock names of the query to be prefixed with the namespace.
+// The columns of the locking functions keep the name MySQL gives them without the namespace,
+// so that the namespace is transparent to the application.
+func (l *Lock) applyNamespace() error {
+	if l.maxNameLength() < 1 {
+		return vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "lock namespace '%s' is too long", l.Namespace)
+	}
+	stmt, err := sqlparser.Parse(l.Query)
+	if err != nil {
+		return vterrors.Wrap(err, "invalid lock query")
+	}
+	if sel, ok := stmt.(*sqlparser.Select); ok {
+		// the locking functions can be shared with the caller.
+		l.LockFuncs = append([]LockFunc(nil), l.LockFuncs...)
+		for i, lf := range l.LockFuncs {
+			if lf.ColumnName != "" || lf.Column >= len(sel.SelectExprs) {
+				continue
+			}
+			if expr, ok := sel.SelectExprs[lf.Column].(*sqlparser.AliasedExpr); ok {
+				l.LockFuncs[i].ColumnName = sqlparser.String(expr.Expr)
+			}
+		}
+	}
+	prefix := l.namespaced("")
+	sqlparser.Rewrite(stmt, func(cursor *sqlparser.Cursor) bool {
+		fn, ok := cursor.Node().(*sqlparser.FuncExpr)
+		if !ok || len(fn.Exprs) == 0 {
+			return true
+		}
+		switch fn.Name.Lowered() {
+		case GetLock.String(), ReleaseLock.String(), IsFreeLock.String(), IsUsedLock.String():
+		default:
+			return true
+		}
+		arg, ok := fn.Exprs[0].(*sqlparser.AliasedExpr)
+		if !ok {
+			return true
+		}
+		if lit, ok := arg.Expr.(*sqlparser.Literal); ok && (lit.Type == sqlparser.StrVal || lit.Type == sqlparser.IntVal) {
+			arg.Expr = sqlparser.NewStrLiteral([]byte(prefix + string(lit.Val)))
+			return true
+		}
+		arg.Expr = &sqlparser.FuncExpr{
+			Name: sqlparser.NewColIdent("concat"),
+			Exprs: sqlparser.SelectExprs{
+				&sqlparser.AliasedExpr{Expr: sqlparser.NewStrLiteral([]byte(prefix))},
+				&sqlparser.AliasedExpr{Expr: arg.Expr},
+			},
+		}
+		return true
+	}, nil)
+	l.Query = sqlparser.String(stmt)
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestLockNamespace(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	name := sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}
+	newLock := func(namespace, query string, typ LockFuncType) *Lock {
+		l, err := NewLock(ks, key.DestinationKeyspaceID{0}, query, WithNamespace(namespace),
+			WithLockFuncs(LockFunc{Type: typ, Name: name}))
+		require.NoError(t, err)
+		return l
+	}
+	getLock1 := newLock("app1", "select get_lock('lock name', 10) from dual", GetLock)
+	getLock2 := newLock("app2", "select get_lock('lock name', 10) from dual", GetLock)
+	releaseLock1 := newLock("app1", "select release_lock('lock name') from dual", ReleaseLock)
+	isFreeLock2 := newLock("app2", "select is_free_lock('lock name') from dual", IsFreeLock)
+	assert.Equal(t, "select get_lock('app1:lock name', 10) from dual", getLock1.Query)
+	assert.Equal(t, "app1", getLock1.description().Other["Namespace"])
+
+	vc := &loggingVCursor{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('app1:lock name', 10)", "int64"), "1"),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('app2:lock name', 10)", "int64"), "1"),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("release_lock('app1:lock name')", "int64"), "1"),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("is_free_lock('app2:lock name')", "int64"), "0"),
+	}}
+	defer lockWaits.releasedAll(vc)
+
+	// the same name in two namespaces is two different locks.
+	qr, err := getLock1.Execute(vc, nil, false)
+	require.NoError(t, err)
+	// the namespace is transparent to the application.
+	assert.Equal(t, "get_lock('lock name', 10)", qr.Fields[0].Name)
+	_, err = getLock2.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, vc.AdvisoryLockCount("app1:lock name"))
+	assert.EqualValues(t, 1, vc.AdvisoryLockCount("app2:lock name"))
+	assert.Zero(t, vc.AdvisoryLockCount("lock name"))
+
+	// releasing the lock of a namespace leaves the other one held.
+	_, err = releaseLock1.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.Zero(t, vc.AdvisoryLockCount("app1:lock name"))
+	assert.EqualValues(t, 1, vc.AdvisoryLockCount("app2:lock name"))
+
+	qr, err = isFreeLock2.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "is_free_lock('lock name')", qr.Fields[0].Name)
+
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select get_lock('app1:lock name', 10) from dual {}",
+		"SetAdvisoryLockCount app1:lock name 1",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select get_lock('app2:lock name', 10) from dual {}",
+		"SetAdvisoryLockCount app2:lock name 1",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select release_lock('app1:lock name') from dual {}",
+		"SetAdvisoryLockCount app1:lock name 0",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select is_free_lock('app2:lock name') from dual {}",
+	})
+}
+
+func TestLockNamespaceBindVar(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock(:lock_name, 10) from dual",
+		WithNamespace("app"), WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Key: "lock_name"}}))
+	require.NoError(t, err)
+	assert.Equal(t, "select get_lock(concat('app:', :lock_name), 10) from dual", l.Query)
+
+	vc := &loggingVCursor{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(concat('app:', :lock_name), 10)", "int64"), "1"),
+	}}
+	defer lockWaits.releasedAll(vc)
+	qr, err := l.Execute(vc, map[string]*querypb.BindVariable{"lock_name": sqltypes.StringBindVariable("lock name")}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "get_lock(:lock_name, 10)", qr.Fields[0].Name)
+	assert.EqualValues(t, 1, vc.AdvisoryLockCount("app:lock name"))
+
+	// MySQL limits the length of the name including the namespace.
+	vc.Rewind()
+	_, err = l.Execute(vc, map[string]*querypb.BindVariable{"lock_name": sqltypes.StringBindVariable(strings.Repeat("x", 61))}, false)
+	require.EqualError(t, err, "incorrect user-level lock name '"+strings.Repeat("x", 61)+"': it must have between 1 and 60 characters")
+	vc.ExpectLog(t, nil)
+
+	_, err = NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('a', 10) from dual", WithNamespace(strings.Repeat("x", 64)))
+	require.EqualError(t, err, "lock namespace '"+strings.Repeat("x", 64)+"' is too long")
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// lockQueues is the queue of the acquisitions of the fair Lock primitives.
+var lockQueues = newLockQueue()
+
+// lockQueue serializes the acquisitions of the advisory locks, per keyspace, shard and lock name,
+// in arrival order.
+// Only the first acquisition in line for a lock sends its lock query, so when the lock is released,
+// MySQL can only hand it to the earliest waiter instead of an arbitrary one.
+type lockQueue struct {
+	mu sync.Mutex
+	// queues are the acquisitions waiting for each lock, in arrival order.
+	queues map[lockKey][]*lockQueueEntry
+}
+
+type lockQueueEntry struct {
+	keys []lockKey
+	// ready is closed once the entry is the first in line for all its locks.
+	ready chan struct{}
+	// isReady is set when ready is closed.
+	isReady bool
+}
+
+func newLockQueue() *lockQueue {
+	return &lockQueue{queues: make(map[lockKey][]*lockQueueEntry)}
+}
+
+// enter queues an acquisition of the locks, and waits until it is the first in line
+// for all of them, or until the context is done. Since the entries are queued for all
+// their locks at once, the earliest entry is always first in line, and can go on.
+// The returned function must be called once the lock query returned.
+func (q *lockQueue) enter(ctx context.Context, keys []lockKey) (func(), error) {
+	e := &lockQueueEntry{keys: keys, ready: make(chan struct{})}
+	q.mu.Lock()
+	for _, key := range keys {
+		q.queues[key] = append(q.queues[key], e)
+	}
+	q.wake(e)
+	q.mu.Unlock()
+
+	leave := func() { q.leave(e) }
+	select {
+	case <-e.ready:
+		return leave, nil
+	case <-ctx.Done():
+		leave()
+		return nil, vterrors.Errorf(vtrpc.Code_DEADLINE_EXCEEDED, "timed out in the queue of advisory lock '%s'", e.keys[0].name)
+	}
+}
+
+// leave removes the entry from the queues, and wakes up the entries that are now first in line.
+func (q *lockQueue) leave(e *lockQueueEntry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, key := range e.keys {
+		queue := q.queues[key]
+		for i, other := range queue {
+			if other == e {
+				queue = append(queue[:i:i], queue[i+1:]...)
+				break
+			}
+		}
+		if len(queue) == 0 {
+			delete(q.queues, key)
+			continue
+		}
+		q.queues[key] = queue
+		q.wake(queue[0])
+	}
+}
+
+// wake closes the ready channel of the entry if it is first in line for all its locks.
+// It must be called with the mutex held.
+func (q *lockQueue) wake(e *lockQueueEntry) {
+	if e.isReady {
+		return
+	}
+	for _, key := range e.keys {
+		if q.queues[key][0] != e {
+			return
+		}
+	}
+	e.isReady = true
+	close(e.ready)
+}
+
+// waiting returns the number of acquisitions queued for the lock, including the one first in line.
+func (q *lockQueue) waiting(key lockKey) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.queues[key])
+}
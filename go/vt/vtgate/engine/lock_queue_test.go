@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// waitQueued waits until the given number of acquisitions are queued for the lock.
+func waitQueued(t *testing.T, q *lockQueue, lock lockKey, count int) {
+	t.Helper()
+	for start := time.Now(); q.waiting(lock) != count; time.Sleep(time.Millisecond) {
+		require.Less(t, int64(time.Since(start)), int64(5*time.Second), "acquisitions of %s did not get queued", lock.name)
+	}
+}
+
+func TestLockQueue(t *testing.T) {
+	q := newLockQueue()
+	ctx := context.Background()
+	l := lockKey{keyspace: "ks", shard: "-20", name: "l"}
+	a := lockKey{keyspace: "ks", shard: "-20", name: "a"}
+	b := lockKey{keyspace: "ks", shard: "-20", name: "b"}
+
+	// the first acquisition goes on right away.
+	leave1, err := q.enter(ctx, []lockKey{l})
+	require.NoError(t, err)
+	// the lock of the same name on another shard is another lock.
+	leaveOther, err := q.enter(ctx, []lockKey{{keyspace: "ks", shard: "20-", name: "l"}})
+	require.NoError(t, err)
+	leaveOther()
+
+	order := make(chan int, 2)
+	for _, waiter := range []int{2, 3} {
+		waiter := waiter
+		go func() {
+			leave, err := q.enter(ctx, []lockKey{l})
+			if err == nil {
+				order <- waiter
+				leave()
+			}
+		}()
+		waitQueued(t, q, l, waiter)
+	}
+	select {
+	case <-order:
+		t.Fatal("an acquisition went on while the lock was first in line for another one")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// the waiters go on in arrival order.
+	leave1()
+	assert.Equal(t, 2, <-order)
+	assert.Equal(t, 3, <-order)
+	waitQueued(t, q, l, 0)
+
+	// an acquisition of several locks waits until it is first in line for all of them.
+	leaveA, err := q.enter(ctx, []lockKey{a})
+	require.NoError(t, err)
+	done := make(chan struct{})
+	go func() {
+		leave, err := q.enter(ctx, []lockKey{a, b})
+		if err == nil {
+			leave()
+		}
+		close(done)
+	}()
+	waitQueued(t, q, b, 1)
+	// "b" cannot go on before the acquisition of "a" and "b", which arrived first.
+	ctxB, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = q.enter(ctxB, []lockKey{b})
+	require.EqualError(t, err, "timed out in the queue of advisory lock 'b'")
+	assert.Equal(t, 1, q.waiting(b))
+	leaveA()
+	<-done
+	assert.Empty(t, q.queues)
+}
+
+func TestLockFairQueue(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('fair lock', 10) from dual",
+		WithFairQueue(),
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("fair lock")}}))
+	require.NoError(t, err)
+	assert.Equal(t, true, l.description().Other["Fair"])
+	fairLock := lockKey{keyspace: "ks", shard: "-20", name: "fair lock"}
+
+	// another acquisition is first in line.
+	leave, err := lockQueues.enter(context.Background(), []lockKey{fairLock})
+	require.NoError(t, err)
+
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('fair lock', 10)", "int64"), "1")
+	vc := &loggingVCursor{results: []*sqltypes.Result{acquired, acquired}}
+	defer lockWaits.releasedAll(vc)
+	done := make(chan error)
+	go func() {
+		_, err := l.Execute(vc, nil, false)
+		done <- err
+	}()
+	waitQueued(t, lockQueues, fairLock, 2)
+	select {
+	case <-done:
+		t.Fatal("the lock query was sent before the acquisition first in line was done")
+	case <-time.After(10 * time.Millisecond):
+	}
+	leave()
+	require.NoError(t, <-done)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select get_lock('fair lock', 10) from dual {}",
+		"SetAdvisoryLockCount fair lock 1",
+	})
+	assert.Zero(t, lockQueues.waiting(fairLock))
+
+	// the session holds the lock: acquiring it again does not queue up behind the other waiters.
+	leave, err = lockQueues.enter(context.Background(), []lockKey{fairLock})
+	require.NoError(t, err)
+	defer leave()
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+}
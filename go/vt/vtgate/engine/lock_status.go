@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*LockStatus)(nil)
+
+// lockStatusFields are the fields of the LockStatus result.
+var lockStatusFields = []*querypb.Field{
+	{Name: "Lock_name", Type: sqltypes.VarChar},
+	{Name: "Acquired", Type: sqltypes.Int64},
+	{Name: "Wait_ms", Type: sqltypes.Int64},
+	{Name: "Connection_id", Type: sqltypes.Int64},
+}
+
+// LockStatus primitive is a diagnostic variant of a Lock acquiring a single lock, for admin tooling.
+// Instead of the MySQL compatible result of GET_LOCK, it returns a single row with the lock name,
+// whether the lock was acquired, the time spent waiting for it, in milliseconds, and the id of the
+// reserved connection holding the locks of the session, NULL if there is none.
+// Use NewLockStatus to create one.
+type LockStatus struct {
+	// Lock is the primitive acquiring the lock.
+	Lock *Lock
+
+	// now is the clock of the wait time. It is time.Now outside of tests.
+	now func() time.Time
+
+	noTxNeeded
+}
+
+// NewLockStatus creates a LockStatus primitive reporting on the lock acquired by l.
+// The lock query must select a single GET_LOCK.
+func NewLockStatus(l *Lock) (*LockStatus, error) {
+	if l == nil || len(l.LockFuncs) != 1 || l.LockFuncs[0].Type != GetLock {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "lock status primitive requires a lock query selecting a single get_lock")
+	}
+	return &LockStatus{Lock: l, now: time.Now}, nil
+}
+
+// RouteType is part of the Primitive interface
+func (s *LockStatus) RouteType() RouteType {
+	return RouteTypeLockStatus
+}
+
+// GetKeyspaceName is part of the Primitive interface
+func (s *LockStatus) GetKeyspaceName() string {
+	return s.Lock.GetKeyspaceName()
+}
+
+// GetTableName is part of the Primitive interface
+func (s *LockStatus) GetTableName() string {
+	return s.Lock.GetTableName()
+}
+
+// HasSideEffects is part of the Primitive interface.
+// It acquires the lock of its Lock.
+func (s *LockStatus) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory is part of the Primitive interface.
+func (s *LockStatus) EstimatedMemory() int64 {
+	return primitiveMemory + inputsMemory(s)
+}
+
+// Execute is part of the Primitive interface
+func (s *LockStatus) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	names, err := s.Lock.resolveLockNames(bindVars)
+	if err != nil {
+		return nil, err
+	}
+	start := s.now()
+	qr, err := s.Lock.Execute(vcursor, bindVars, wantfields)
+	if err != nil {
+		return nil, err
+	}
+	wait := s.now().Sub(start)
+
+	acquired := int64(0)
+	if column := s.Lock.LockFuncs[0].Column; len(qr.Rows) == 1 && column < len(qr.Rows[0]) && qr.Rows[0][column].ToString() == "1" {
+		acquired = 1
+	}
+	connID := sqltypes.NULL
+	if id := vcursor.ReservedConnID(); id != 0 {
+		connID = sqltypes.NewInt64(id)
+	}
+	return &sqltypes.Result{
+		Fields: lockStatusFields,
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewVarChar(names[0]),
+			sqltypes.NewInt64(acquired),
+			sqltypes.NewInt64(wait.Milliseconds()),
+			connID,
+		}},
+		RowsAffected: 1,
+	}, nil
+}
+
+// StreamExecute is part of the Primitive interface
+func (s *LockStatus) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	qr, err := s.Execute(vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// GetFields is part of the Primitive interface
+func (s *LockStatus) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: lockStatusFields}, nil
+}
+
+// Inputs is part of the Primitive interface
+func (s *LockStatus) Inputs() []Primitive {
+	return []Primitive{s.Lock}
+}
+
+func (s *LockStatus) description() PrimitiveDescription {
+	return PrimitiveDescription{OperatorType: "LockStatus"}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestNewLockStatus(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('a', 10), get_lock('b', 10) from dual",
+		WithLockFuncs(
+			LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("a")}},
+			LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("b")}, Column: 1},
+		))
+	require.NoError(t, err)
+	_, err = NewLockStatus(l)
+	require.EqualError(t, err, "lock status primitive requires a lock query selecting a single get_lock")
+}
+
+func TestLockStatus(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('status lock', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("status lock")}}))
+	require.NoError(t, err)
+	s, err := NewLockStatus(l)
+	require.NoError(t, err)
+	clock := time.Now()
+	s.now = func() time.Time {
+		// every reading of the clock is 250ms after the previous one.
+		clock = clock.Add(250 * time.Millisecond)
+		return clock
+	}
+
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('status lock', 10)", "int64"), "1")
+	vc := &loggingVCursor{results: []*sqltypes.Result{acquired}, reservedConnID: 42}
+	defer lockWaits.releasedAll(vc)
+	qr, err := s.Execute(vc, nil, true)
+	require.NoError(t, err)
+	want := sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("Lock_name|Acquired|Wait_ms|Connection_id", "varchar|int64|int64|int64"),
+		"status lock|1|250|42",
+	)
+	assert.Equal(t, want, qr)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select get_lock('status lock', 10) from dual {}",
+		"SetAdvisoryLockCount status lock 1",
+	})
+
+	// the lock is held by another session: it is not acquired, and the session has no reserved connection.
+	notAcquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('status lock', 10)", "int64"), "0")
+	vc = &loggingVCursor{results: []*sqltypes.Result{notAcquired}}
+	qr, err = s.Execute(vc, nil, true)
+	require.NoError(t, err)
+	want = sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("Lock_name|Acquired|Wait_ms|Connection_id", "varchar|int64|int64|int64"),
+		"status lock|0|250|null",
+	)
+	assert.Equal(t, want, qr)
+
+	fields, err := s.GetFields(vc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: want.Fields}, fields)
+	assert.Equal(t, "LockStatus", PrimitiveToPlanDescription(s).OperatorType)
+}
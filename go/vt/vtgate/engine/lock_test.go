@@ -0,0 +1,1361 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestNewLock(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	query := "select get_lock('lock name', 10) from dual"
+
+	l, err := NewLock(ks, key.DestinationKeyspaceID{0}, query)
+	require.NoError(t, err)
+	assert.Equal(t, &Lock{
+		Keyspace:          ks,
+		TargetDestination: key.DestinationKeyspaceID{0},
+		Query:             query,
+	}, l)
+
+	_, err = NewLock(nil, key.DestinationKeyspaceID{0}, query)
+	require.EqualError(t, err, "lock primitive requires a keyspace")
+
+	_, err = NewLock(ks, nil, query)
+	require.EqualError(t, err, "lock primitive requires a target destination")
+
+	_, err = NewLock(ks, key.DestinationKeyspaceID{0}, "")
+	require.EqualError(t, err, "lock primitive requires a query")
+}
+
+func TestNewLockOptions(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	query := "select get_lock('lock name', 10) from dual"
+
+	l, err := NewLock(ks, key.DestinationKeyspaceID{0}, query)
+	require.NoError(t, err)
+	assert.Zero(t, l.Timeout)
+	assert.False(t, l.PrimaryOnly)
+	assert.Zero(t, l.Retries)
+
+	l, err = NewLock(ks, key.DestinationKeyspaceID{0}, query, WithTimeout(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, l.Timeout)
+
+	l, err = NewLock(ks, key.DestinationKeyspaceID{0}, query, WithPrimaryOnly())
+	require.NoError(t, err)
+	assert.True(t, l.PrimaryOnly)
+
+	l, err = NewLock(ks, key.DestinationKeyspaceID{0}, query, WithRetry(3))
+	require.NoError(t, err)
+	assert.Equal(t, 3, l.Retries)
+
+	_, err = NewLock(ks, key.DestinationKeyspaceID{0}, query, WithTimeout(-time.Second))
+	require.EqualError(t, err, "invalid lock timeout: -1s")
+
+	_, err = NewLock(ks, key.DestinationKeyspaceID{0}, query, WithRetry(-1))
+	require.EqualError(t, err, "invalid lock retries: -1")
+}
+
+func TestLockPrimaryOnly(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual", WithPrimaryOnly())
+	require.NoError(t, err)
+
+	vc := &loggingVCursor{resolvedTargetTabletType: topodatapb.TabletType_REPLICA}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "lock query can only be sent to a primary tablet, got: REPLICA")
+
+	vc = &loggingVCursor{resolvedTargetTabletType: topodatapb.TabletType_MASTER}
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+}
+
+func TestLockExecuteOnShards(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	rs := &srvtopo.ResolvedShard{Target: &querypb.Target{Keyspace: "ks", Shard: "80-", TabletType: topodatapb.TabletType_MASTER}}
+
+	// the destination of the primitive is not resolved again.
+	vc := &loggingVCursor{}
+	_, err = l.ExecuteOnShards(vc, []*srvtopo.ResolvedShard{rs}, nil)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		"ExecuteLock ks.80-: select get_lock('lock name', 10) from dual {}",
+	})
+
+	vc.Rewind()
+	_, err = l.ExecuteOnShards(vc, nil, nil)
+	require.EqualError(t, err, "lock query must be sent to exactly one shard, got: 0")
+	_, err = l.ExecuteOnShards(vc, []*srvtopo.ResolvedShard{rs, rs}, nil)
+	require.EqualError(t, err, "lock query must be sent to exactly one shard, got: 2")
+	assert.Equal(t, vtrpc.Code_INVALID_ARGUMENT, vterrors.Code(err))
+	vc.ExpectLog(t, nil)
+}
+
+func TestLockPrimaryReadOnly(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual", WithPrimaryOnly())
+	require.NoError(t, err)
+
+	// the primary is being demoted.
+	vc := &loggingVCursor{resolvedTargetTabletType: topodatapb.TabletType_MASTER, targetReadOnly: true}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "lock query cannot be sent to the read-only primary tablet of ks/-20")
+	assert.Equal(t, vtrpc.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	vc.ExpectLog(t, []string{"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)"})
+
+	// the serving state only matters to primary-only locks.
+	l.PrimaryOnly = false
+	vc.Rewind()
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+}
+
+func TestLockRetry(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual", WithRetry(1))
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1")
+	vc := &loggingVCursor{
+		results:   []*sqltypes.Result{nil, want},
+		resultErr: errors.New("connection reset"),
+	}
+	qr, err := l.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, want, qr)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select get_lock('lock name', 10) from dual {}",
+		"ExecuteLock ks.-20: select get_lock('lock name', 10) from dual {}",
+	})
+
+	vc.Rewind()
+	vc.results = []*sqltypes.Result{nil, nil}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "connection reset")
+}
+
+func TestLockWithTimeout(t *testing.T) {
+	prototype, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual", WithTimeout(time.Second))
+	require.NoError(t, err)
+
+	l := prototype.WithTimeout(time.Minute)
+	assert.Equal(t, time.Minute, l.Timeout)
+	assert.Equal(t, time.Second, prototype.Timeout)
+	assert.Equal(t, "select get_lock('lock name', 60) from dual", l.Query)
+	assert.Equal(t, "select get_lock('lock name', 1) from dual", prototype.Query)
+
+	// the copy must not share the keyspace with the prototype
+	assert.False(t, prototype.Keyspace == l.Keyspace)
+	l.Keyspace.Name = "other"
+	assert.Equal(t, "ks", prototype.Keyspace.Name)
+}
+
+func TestLockAlignTimeout(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	tcases := []struct {
+		query   string
+		timeout time.Duration
+		want    string
+	}{{
+		query:   "select get_lock('lock name', 10) from dual",
+		timeout: 5 * time.Second,
+		want:    "select get_lock('lock name', 5) from dual",
+	}, {
+		query:   "select get_lock('lock name', 10) from dual",
+		timeout: 1500 * time.Millisecond,
+		want:    "select get_lock('lock name', 1.5) from dual",
+	}, {
+		query:   "select get_lock(:name, :timeout), release_lock('other') from dual",
+		timeout: time.Minute,
+		want:    "select get_lock(:name, 60), release_lock('other') from dual",
+	}, {
+		// the query is kept as is when it already uses the timeout.
+		query:   "SELECT GET_LOCK('lock name', 10) FROM dual",
+		timeout: 10 * time.Second,
+		want:    "SELECT GET_LOCK('lock name', 10) FROM dual",
+	}, {
+		query:   "select get_lock('lock name', 10) from dual",
+		timeout: 0,
+		want:    "select get_lock('lock name', 10) from dual",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			l, err := NewLock(ks, key.DestinationKeyspaceID{0}, tcase.query, WithTimeout(tcase.timeout))
+			require.NoError(t, err)
+			assert.Equal(t, tcase.want, l.Query)
+		})
+	}
+
+	_, err := NewLock(ks, key.DestinationKeyspaceID{0}, "not a query", WithTimeout(time.Second))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid lock query: ")
+}
+
+// deadlineVCursor records the deadline of the context of the lock query.
+type deadlineVCursor struct {
+	*loggingVCursor
+	deadline time.Time
+	ok       bool
+}
+
+func (vc *deadlineVCursor) ExecuteLock(rs *srvtopo.ResolvedShard, query *querypb.BoundQuery) (*sqltypes.Result, error) {
+	vc.deadline, vc.ok = vc.Context().Deadline()
+	return vc.loggingVCursor.ExecuteLock(rs, query)
+}
+
+func TestLockDeadline(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual", WithTimeout(time.Minute))
+	require.NoError(t, err)
+
+	vc := &deadlineVCursor{loggingVCursor: &loggingVCursor{}}
+	before := time.Now()
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+	require.True(t, vc.ok)
+	assert.False(t, vc.deadline.Before(before.Add(time.Minute)), "deadline %v is before %v", vc.deadline, before.Add(time.Minute))
+	assert.False(t, vc.deadline.After(time.Now().Add(time.Minute)), "deadline %v is too late", vc.deadline)
+
+	// the later queries of the plan do not inherit the deadline of the lock query.
+	_, ok := vc.Context().Deadline()
+	assert.False(t, ok)
+
+	// without a timeout, the lock query has no deadline.
+	l.Timeout = 0
+	vc = &deadlineVCursor{loggingVCursor: &loggingVCursor{}}
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.False(t, vc.ok)
+}
+
+func TestLockString(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	assert.Equal(t, "Lock{ks, DestinationKeyspaceID(00), select get_lock(:redacted1, :redacted2) from dual}", l.String())
+	assert.Equal(t, l.String(), fmt.Sprintf("%v", l))
+
+	l.Query = "not a query"
+	assert.Equal(t, "Lock{ks, DestinationKeyspaceID(00), <unparsable query>}", l.String())
+}
+
+func TestLockRouteType(t *testing.T) {
+	l := &Lock{}
+	assert.Equal(t, RouteTypeLock, l.RouteType())
+	assert.Equal(t, "lock", l.RouteType().String())
+	assert.Equal(t, "lock", RouteTypeString(l))
+}
+
+func TestLockTableName(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	assert.Equal(t, "dual", l.GetTableName())
+	assert.NotContains(t, l.description().Other, "TableName")
+
+	l, err = NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from locks", WithTableName("locks"))
+	require.NoError(t, err)
+	assert.Equal(t, "locks", l.GetTableName())
+	assert.Equal(t, "locks", l.description().Other["TableName"])
+	assert.Equal(t, "locks", l.Clone().GetTableName())
+}
+
+func TestLockReentrant(t *testing.T) {
+	name := sqltypes.PlanValue{Key: "name"}
+	getLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock(:name, 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: name}))
+	require.NoError(t, err)
+	releaseLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select release_lock(:name) from dual",
+		WithLockFuncs(LockFunc{Type: ReleaseLock, Name: name}))
+	require.NoError(t, err)
+
+	bv := map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable("lock name")}
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(:name, 10)", "int64"), "1")
+	released := sqltypes.MakeTestResult(sqltypes.MakeTestFields("release_lock(:name)", "int64"), "1")
+	notHeld := sqltypes.MakeTestResult(sqltypes.MakeTestFields("release_lock(:name)", "int64"), "null")
+	vc := &loggingVCursor{results: []*sqltypes.Result{acquired, acquired, released, released, notHeld}}
+
+	// the same session acquires the lock twice.
+	_, err = getLock.Execute(vc, bv, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, vc.AdvisoryLockCount("lock name"))
+	_, err = getLock.Execute(vc, bv, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, vc.AdvisoryLockCount("lock name"))
+
+	// and has to release it twice.
+	_, err = releaseLock.Execute(vc, bv, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, vc.AdvisoryLockCount("lock name"))
+	_, err = releaseLock.Execute(vc, bv, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, vc.AdvisoryLockCount("lock name"))
+	assert.Empty(t, vc.advisoryLocks)
+
+	// releasing a lock that is not held anymore does not change the session.
+	_, err = releaseLock.Execute(vc, bv, false)
+	require.NoError(t, err)
+	assert.Empty(t, vc.advisoryLocks)
+
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteLock ks.-20: select get_lock(:name, 10) from dual {name: type:VARBINARY value:"lock name" }`,
+		"SetAdvisoryLockCount lock name 1",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteLock ks.-20: select get_lock(:name, 10) from dual {name: type:VARBINARY value:"lock name" }`,
+		"SetAdvisoryLockCount lock name 2",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteLock ks.-20: select release_lock(:name) from dual {name: type:VARBINARY value:"lock name" }`,
+		"SetAdvisoryLockCount lock name 1",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteLock ks.-20: select release_lock(:name) from dual {name: type:VARBINARY value:"lock name" }`,
+		"SetAdvisoryLockCount lock name 0",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteLock ks.-20: select release_lock(:name) from dual {name: type:VARBINARY value:"lock name" }`,
+	})
+}
+
+func TestLockResultType(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual",
+		WithLockFuncs(LockFunc{
+			Type:       GetLock,
+			Name:       sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")},
+			ColumnName: "get_lock('lock name', 10)",
+		}))
+	require.NoError(t, err)
+
+	// the tablet answers with a column that does not match what MySQL returns.
+	tabletResult := sqltypes.MakeTestResult(sqltypes.MakeTestFields("col", "varchar"), "1")
+	vc := &loggingVCursor{results: []*sqltypes.Result{tabletResult}}
+	qr, err := l.Execute(vc, nil, true)
+	require.NoError(t, err)
+
+	want := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1")
+	assert.Equal(t, want, qr)
+	assert.Equal(t, querypb.Type_INT64, qr.Fields[0].Type)
+	assert.Equal(t, "get_lock('lock name', 10)", qr.Fields[0].Name)
+
+	// the tablet result is left untouched.
+	assert.Equal(t, querypb.Type_VARCHAR, tabletResult.Fields[0].Type)
+}
+
+func TestLockGetFields(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock(:name, 10) from dual",
+		WithRetry(2),
+		WithLockFuncs(LockFunc{
+			Type:       GetLock,
+			Name:       sqltypes.PlanValue{Key: "name"},
+			ColumnName: "get_lock(:name, 10)",
+		}))
+	require.NoError(t, err)
+
+	bv := map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable("lock name")}
+	vc := &loggingVCursor{results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(:name, 10)", "varchar"))}}
+	qr, err := l.GetFields(vc, bv)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: sqltypes.MakeTestFields("get_lock(:name, 10)", "int64")}, qr)
+
+	// the locking function is not evaluated, so the session does not hold the lock.
+	assert.Empty(t, vc.advisoryLocks)
+	assert.Equal(t, "select get_lock(:name, 10) from dual", l.Query)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteStandalone select get_lock(:name, 10) from dual where 1 != 1 name: type:VARBINARY value:"lock name"  ks -20`,
+	})
+
+	// the lock name of a prepared statement is a NULL placeholder: it is not resolved.
+	vc.Rewind()
+	qr, err = l.GetFields(vc, map[string]*querypb.BindVariable{"name": sqltypes.NullBindVariable})
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: sqltypes.MakeTestFields("get_lock(:name, 10)", "int64")}, qr)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteStandalone select get_lock(:name, 10) from dual where 1 != 1 name: type:NULL_TYPE  ks -20",
+	})
+}
+
+func TestLockColumnAlias(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) as got from dual",
+		WithColumnAlias("got"),
+		WithLockFuncs(LockFunc{
+			Type:       GetLock,
+			Name:       sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")},
+			ColumnName: "get_lock('lock name', 10)",
+		}))
+	require.NoError(t, err)
+	assert.Equal(t, "got", l.description().Other["ColumnAlias"])
+
+	vc := &loggingVCursor{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("col", "varchar")),
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("col", "varchar"), "1"),
+	}}
+	qr, err := l.GetFields(vc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: sqltypes.MakeTestFields("got", "int64")}, qr)
+
+	qr, err = l.Execute(vc, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields("got", "int64"), "1"), qr)
+
+	// without locking functions, the alias names the first column.
+	l, err = NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) as got from dual", WithColumnAlias("got"))
+	require.NoError(t, err)
+	vc = &loggingVCursor{results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("col", "int64"), "1")}}
+	qr, err = l.Execute(vc, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, "got", qr.Fields[0].Name)
+}
+
+// blockingVCursor blocks the lock query until its context is done.
+type blockingVCursor struct {
+	*loggingVCursor
+}
+
+func (vc *blockingVCursor) ExecuteLock(rs *srvtopo.ResolvedShard, query *querypb.BoundQuery) (*sqltypes.Result, error) {
+	vc.loggingVCursor.ExecuteLock(rs, query)
+	<-vc.Context().Done()
+	return nil, vc.Context().Err()
+}
+
+func (vc *blockingVCursor) ExecuteStandalone(query string, bindvars map[string]*querypb.BindVariable, rs *srvtopo.ResolvedShard) (*sqltypes.Result, error) {
+	vc.loggingVCursor.ExecuteStandalone(query, bindvars, rs)
+	<-vc.Context().Done()
+	return nil, vc.Context().Err()
+}
+
+func TestLockGetFieldsContext(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual", WithRetry(2))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	vc := &blockingVCursor{loggingVCursor: &loggingVCursor{}}
+	start := time.Now()
+	_, err = GetFieldsContext(ctx, l, vc, nil)
+	require.EqualError(t, err, "GetFields: context canceled")
+	assert.Equal(t, vtrpc.Code_CANCELED, vterrors.Code(err))
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+	// the field query is not retried, and the cursor gets its context back.
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteStandalone select get_lock('lock name', 10) from dual where 1 != 1  ks -20",
+	})
+	assert.NoError(t, vc.Context().Err())
+
+	// nothing is sent once the context is canceled.
+	logging := &loggingVCursor{}
+	_, err = GetFieldsContext(ctx, l, logging, nil)
+	require.EqualError(t, err, "GetFields: context canceled")
+	logging.ExpectLog(t, nil)
+
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"))
+	logging = &loggingVCursor{results: []*sqltypes.Result{result}}
+	qr, err := GetFieldsContext(context.Background(), l, logging, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: result.Fields}, qr)
+}
+
+func TestLockDeadlock(t *testing.T) {
+	newGetLock := func(name string) *Lock {
+		l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock(:name, 10) from dual",
+			WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar(name)}}))
+		require.NoError(t, err)
+		return l
+	}
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(:name, 10)", "int64"), "1")
+
+	// session1 holds lock1 and session2 holds lock2.
+	session1 := &loggingVCursor{results: []*sqltypes.Result{acquired}}
+	_, err := newGetLock("deadlock1").Execute(session1, nil, false)
+	require.NoError(t, err)
+	session2 := &loggingVCursor{results: []*sqltypes.Result{acquired}}
+	_, err = newGetLock("deadlock2").Execute(session2, nil, false)
+	require.NoError(t, err)
+	defer lockWaits.releasedAll(session1)
+	defer lockWaits.releasedAll(session2)
+
+	// session1 is blocked waiting for lock2.
+	require.NoError(t, lockWaits.wait(session1, session1.lockTarget, []string{"deadlock2"}))
+	defer lockWaits.done(session1)
+
+	// session2 now waits for lock1: the acquisition is aborted instead of hanging.
+	session2.Rewind()
+	_, err = newGetLock("deadlock1").Execute(session2, nil, false)
+	require.EqualError(t, err, "deadlock found when trying to get lock 'deadlock1'; try releasing locks and restarting lock acquisition")
+	assert.Equal(t, vtrpc.Code_ABORTED, vterrors.Code(err))
+	session2.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+	})
+
+	// re-acquiring a held lock never deadlocks.
+	session2.Rewind()
+	_, err = newGetLock("deadlock2").Execute(session2, nil, false)
+	require.NoError(t, err)
+}
+
+func TestLockWaitGraphShards(t *testing.T) {
+	g := newLockWaitGraph()
+	shard1 := &querypb.Target{Keyspace: "ks", Shard: "-80"}
+	shard2 := &querypb.Target{Keyspace: "ks", Shard: "80-"}
+	session1, session2 := "session1", "session2"
+
+	// the locks of different shards are different locks, even with the same name.
+	g.acquired(session1, shard1, "lock")
+	assert.True(t, g.heldByOther(session2, shard1, "lock"))
+	assert.False(t, g.heldByOther(session2, shard2, "lock"))
+	g.acquired(session2, shard2, "lock")
+	require.NoError(t, g.wait(session1, shard2, []string{"lock"}))
+	require.NoError(t, g.wait(session2, shard2, []string{"lock"}))
+	g.done(session1)
+	g.done(session2)
+
+	// a session without lock connection holds no lock.
+	_, held := g.acquiredAt(nil, shard1, "other lock")
+	assert.False(t, held)
+	g.acquired(nil, shard1, "other lock")
+	assert.False(t, g.heldByOther(session1, shard1, "other lock"))
+
+	g.releasedAll(session1)
+	assert.False(t, g.heldByOther(session2, shard1, "lock"))
+	assert.True(t, g.heldByOther(session1, shard2, "lock"))
+}
+
+func TestLockReportConnID(t *testing.T) {
+	name := sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}
+	getLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual",
+		WithReportConnID(), WithLockFuncs(LockFunc{Type: GetLock, Name: name}))
+	require.NoError(t, err)
+	releaseLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select release_lock('lock name') from dual",
+		WithReportConnID(), WithLockFuncs(LockFunc{Type: ReleaseLock, Name: name}))
+	require.NoError(t, err)
+
+	vc := &loggingVCursor{
+		reservedConnID: 7,
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1"),
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("release_lock('lock name')", "int64"), "1"),
+		},
+	}
+	defer lockWaits.releasedAll(vc)
+
+	_, err = getLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	_, err = releaseLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select get_lock('lock name', 10) from dual {}",
+		"SetAdvisoryLockCount lock name 1",
+		"UDV set with (vt_lock_connection_id,7)",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select release_lock('lock name') from dual {}",
+		"SetAdvisoryLockCount lock name 0",
+		"UDV set with (vt_lock_connection_id,<nil>)",
+	})
+	assert.Equal(t, true, getLock.description().Other["ReportConnID"])
+}
+
+func TestLockWaitTimeout(t *testing.T) {
+	name := sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}
+	getLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual",
+		WithLockWaitTimeout(30*time.Second), WithLockFuncs(LockFunc{Type: GetLock, Name: name}))
+	require.NoError(t, err)
+	isFree, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select is_free_lock('lock name') from dual",
+		WithLockWaitTimeout(30*time.Second), WithLockFuncs(LockFunc{Type: IsFreeLock, Name: name}))
+	require.NoError(t, err)
+
+	vc := &loggingVCursor{
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1"),
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("is_free_lock('lock name')", "int64"), "0"),
+		},
+	}
+	defer lockWaits.releasedAll(vc)
+
+	// the timeout is only set before acquiring locks.
+	_, err = getLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	_, err = isFree.Execute(vc, nil, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"SetLockWaitTimeout ks.-20: 30s",
+		"ExecuteLock ks.-20: select get_lock('lock name', 10) from dual {}",
+		"SetAdvisoryLockCount lock name 1",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select is_free_lock('lock name') from dual {}",
+	})
+	assert.Equal(t, "30s", getLock.description().Other["LockWaitTimeout"])
+}
+
+func TestLockNameBindVar(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock(:lock_name, 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Key: "lock_name"}}))
+	require.NoError(t, err)
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(:lock_name, 10)", "int64"), "1")
+
+	tcases := []struct {
+		name     string
+		bindVars map[string]*querypb.BindVariable
+		err      string
+	}{{
+		name:     "valid",
+		bindVars: map[string]*querypb.BindVariable{"lock_name": sqltypes.StringBindVariable("bind var lock")},
+	}, {
+		name:     "missing",
+		bindVars: map[string]*querypb.BindVariable{},
+		err:      "missing bind var lock_name for the lock name",
+	}, {
+		name:     "null",
+		bindVars: map[string]*querypb.BindVariable{"lock_name": sqltypes.NullBindVariable},
+		err:      "incorrect user-level lock name: NULL",
+	}, {
+		name:     "too long",
+		bindVars: map[string]*querypb.BindVariable{"lock_name": sqltypes.StringBindVariable(strings.Repeat("x", 65))},
+		err:      "incorrect user-level lock name '" + strings.Repeat("x", 65) + "': it must have between 1 and 64 characters",
+	}, {
+		name:     "blob",
+		bindVars: map[string]*querypb.BindVariable{"lock_name": {Type: sqltypes.Blob, Value: []byte("bind var lock")}},
+		err:      "bind var lock_name of type BLOB cannot be used as a lock name",
+	}, {
+		name:     "tuple",
+		bindVars: map[string]*querypb.BindVariable{"lock_name": sqltypes.TestBindVariable([]interface{}{"a", "b"})},
+		err:      "bind var lock_name of type TUPLE cannot be used as a lock name",
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			vc := &loggingVCursor{results: []*sqltypes.Result{acquired}}
+			_, err := l.Execute(vc, tcase.bindVars, false)
+			if tcase.err == "" {
+				require.NoError(t, err)
+				assert.EqualValues(t, 1, vc.AdvisoryLockCount("bind var lock"))
+				lockWaits.releasedAll(vc)
+				return
+			}
+			require.EqualError(t, err, tcase.err)
+			assert.Equal(t, vtrpc.Code_INVALID_ARGUMENT, vterrors.Code(err))
+			// nothing is sent to vttablet.
+			vc.ExpectLog(t, nil)
+		})
+	}
+}
+
+func TestLockMaxAdvisoryLocks(t *testing.T) {
+	save := testMaxAdvisoryLocks
+	testMaxAdvisoryLocks = 2
+	defer func() { testMaxAdvisoryLocks = save }()
+
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock(:name, 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Key: "name"}}))
+	require.NoError(t, err)
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(:name, 10)", "int64"), "1")
+	vc := &loggingVCursor{results: []*sqltypes.Result{acquired, acquired, acquired}}
+	defer lockWaits.releasedAll(vc)
+	getLock := func(name string) error {
+		_, err := l.Execute(vc, map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable(name)}, false)
+		return err
+	}
+
+	// up to the cap.
+	require.NoError(t, getLock("max1"))
+	require.NoError(t, getLock("max2"))
+
+	vc.log = nil
+	err = getLock("max3")
+	require.EqualError(t, err, "too many advisory locks held by the session: the maximum is 2")
+	assert.Equal(t, vtrpc.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.Len(t, vc.advisoryLocks, 2)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+	})
+
+	// a held lock can still be acquired again.
+	require.NoError(t, getLock("max1"))
+	assert.EqualValues(t, 2, vc.AdvisoryLockCount("max1"))
+}
+
+func TestLockAudit(t *testing.T) {
+	ch := LockAuditLogger.Subscribe("test")
+	defer LockAuditLogger.Unsubscribe(ch)
+
+	name := sqltypes.PlanValue{Value: sqltypes.NewVarChar("audited")}
+	getLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('audited', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: name}))
+	require.NoError(t, err)
+	releaseLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select release_lock('audited') from dual",
+		WithLockFuncs(LockFunc{Type: ReleaseLock, Name: name}))
+	require.NoError(t, err)
+
+	ctx := callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID("principal", "component", "subcomponent"), callerid.NewImmediateCallerID("user"))
+	vc := &loggingVCursor{
+		noopVCursor: noopVCursor{ctx: ctx},
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('audited', 10)", "int64"), "1"),
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("release_lock('audited')", "int64"), "1"),
+		},
+	}
+	_, err = getLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	_, err = releaseLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+
+	want := []*LockAuditRecord{{
+		Principal:   "principal",
+		User:        "user",
+		Action:      "get_lock",
+		LockName:    "audited",
+		Keyspace:    "ks",
+		Destination: "DestinationKeyspaceID(00)",
+		Outcome:     "1",
+	}, {
+		Principal:   "principal",
+		User:        "user",
+		Action:      "release_lock",
+		LockName:    "audited",
+		Keyspace:    "ks",
+		Destination: "DestinationKeyspaceID(00)",
+		Outcome:     "1",
+	}}
+	for _, w := range want {
+		got := (<-ch).(*LockAuditRecord)
+		assert.Equal(t, w, got)
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, want[0].Logf(buf, nil))
+	assert.Equal(t, `{"principal":"principal","user":"user","action":"get_lock","lock_name":"audited","keyspace":"ks","destination":"DestinationKeyspaceID(00)","outcome":"1"}`+"\n", buf.String())
+}
+
+func TestLockAuditReservedConnID(t *testing.T) {
+	ch := LockAuditLogger.Subscribe("test")
+	defer LockAuditLogger.Unsubscribe(ch)
+
+	vindex, _ := vindexes.NewHash("", nil)
+	name := sqltypes.PlanValue{Value: sqltypes.NewVarChar("tenant lock")}
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks", Sharded: true}, nil, "select get_lock('tenant lock', 10) from dual",
+		WithVindex(vindex.(vindexes.SingleColumn), sqltypes.PlanValue{Value: sqltypes.NewInt64(1)}),
+		WithLockFuncs(LockFunc{Type: GetLock, Name: name}))
+	require.NoError(t, err)
+
+	vc := &loggingVCursor{
+		results:        []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('tenant lock', 10)", "int64"), "1")},
+		reservedConnID: 42,
+	}
+	defer lockWaits.releasedAll(vc)
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+
+	got := (<-ch).(*LockAuditRecord)
+	// the lock is routed through the vindex, so there is no target destination.
+	assert.Equal(t, &LockAuditRecord{
+		Action:         "get_lock",
+		LockName:       "tenant lock",
+		Keyspace:       "ks",
+		ReservedConnID: 42,
+		Outcome:        "1",
+	}, got)
+	buf := &bytes.Buffer{}
+	require.NoError(t, got.Logf(buf, nil))
+	assert.Contains(t, buf.String(), `"reserved_conn_id":42`)
+}
+
+// contendedVCursor calls onLock while the lock query is in flight.
+type contendedVCursor struct {
+	*loggingVCursor
+	onLock func()
+}
+
+func (vc *contendedVCursor) ExecuteLock(rs *srvtopo.ResolvedShard, query *querypb.BoundQuery) (*sqltypes.Result, error) {
+	vc.onLock()
+	return vc.loggingVCursor.ExecuteLock(rs, query)
+}
+
+func TestLockContentionMetrics(t *testing.T) {
+	now := time.Unix(0, 0)
+	defer func(f func() time.Time) { lockContention.now = f }(lockContention.now)
+	lockContention.now = func() time.Time { return now }
+
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('contended', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("contended")}}))
+	require.NoError(t, err)
+
+	waitCount := lockContention.waitTimes.Counts()["contended"]
+	vc := &contendedVCursor{
+		loggingVCursor: &loggingVCursor{results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock", "int64"), "1")}},
+		onLock: func() {
+			// the session is waiting for the lock.
+			assert.EqualValues(t, 1, lockContention.waiters.Counts()["contended"])
+			now = now.Add(3 * time.Second)
+		},
+	}
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+	defer lockWaits.releasedAll(vc.loggingVCursor)
+
+	assert.EqualValues(t, 0, lockContention.waiters.Counts()["contended"])
+	assert.EqualValues(t, waitCount+1, lockContention.waitTimes.Counts()["contended"])
+	assert.EqualValues(t, 3*time.Second, lockContention.waitTimes.Histograms()["contended"].Total())
+}
+
+func TestLockMetricsLabel(t *testing.T) {
+	assert.Equal(t, "lock name", lockMetricsLabel("lock name"))
+
+	defer func() { LockMetricsNameBuckets = 0 }()
+	LockMetricsNameBuckets = 4
+	label := lockMetricsLabel("lock name")
+	assert.Contains(t, []string{"bucket0", "bucket1", "bucket2", "bucket3"}, label)
+	assert.Equal(t, label, lockMetricsLabel("lock name"))
+}
+
+func TestLockInTransaction(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('tx lock', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("tx lock")}}))
+	require.NoError(t, err)
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('tx lock', 10)", "int64"), "1")
+
+	// out of a transaction, no warning is recorded.
+	vc := &loggingVCursor{results: []*sqltypes.Result{acquired}}
+	defer lockWaits.releasedAll(vc)
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.Empty(t, vc.warnings)
+
+	// in a transaction, the lock outlives the transaction.
+	vc = &loggingVCursor{results: []*sqltypes.Result{acquired}, inTransaction: true}
+	defer lockWaits.releasedAll(vc)
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, []*querypb.QueryWarning{{
+		Message: "advisory lock 'tx lock' acquired in a transaction is not released when the transaction ends",
+	}}, vc.warnings)
+}
+
+func TestLockReservedConnDisabled(t *testing.T) {
+	defer func() { testReservedConnDisabled = false }()
+	testReservedConnDisabled = true
+
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	vc := &loggingVCursor{}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "advisory locks require reserved connections, which are disabled")
+	assert.Equal(t, vtrpc.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	vc.ExpectLog(t, nil)
+}
+
+func TestLockAdvisoryLocksDisabled(t *testing.T) {
+	defer func() { testAdvisoryLocksDisabled = false }()
+	testAdvisoryLocksDisabled = true
+
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	vc := &loggingVCursor{}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "advisory locks are disabled")
+	assert.Equal(t, vtrpc.Code_UNIMPLEMENTED, vterrors.Code(err))
+	_, err = l.GetFields(vc, nil)
+	require.EqualError(t, err, "advisory locks are disabled")
+	vc.ExpectLog(t, nil)
+}
+
+func TestLockKeyspaceDenied(t *testing.T) {
+	allowed, err := NewLock(&vindexes.Keyspace{Name: "allowed"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}}))
+	require.NoError(t, err)
+	denied, err := NewLock(&vindexes.Keyspace{Name: "denied", AdvisoryLocksDenied: true}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}}))
+	require.NoError(t, err)
+
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1")
+	vc := &loggingVCursor{results: []*sqltypes.Result{acquired}}
+	defer lockWaits.releasedAll(vc)
+	_, err = allowed.Execute(vc, nil, false)
+	require.NoError(t, err)
+
+	// nothing is sent to the keyspace denying the locks.
+	_, err = denied.Execute(vc, nil, false)
+	require.EqualError(t, err, "advisory locks are not allowed in keyspace denied")
+	assert.Equal(t, vtrpc.Code_PERMISSION_DENIED, vterrors.Code(err))
+	assert.EqualValues(t, 1, vc.AdvisoryLockCount("lock name"))
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations allowed [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock allowed.-20: select get_lock('lock name', 10) from dual {}",
+		"SetAdvisoryLockCount lock name 1",
+	})
+}
+
+func TestLockStreamExecute(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('stream lock', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("stream lock")}}))
+	require.NoError(t, err)
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('stream lock', 10)", "int64"), "1")
+
+	vc := &loggingVCursor{results: []*sqltypes.Result{acquired, acquired}}
+	defer lockWaits.releasedAll(vc)
+	var got []*sqltypes.Result
+	err = l.StreamExecute(vc, nil, true, func(qr *sqltypes.Result) error {
+		got = append(got, qr)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, &sqltypes.Result{Fields: acquired.Fields}, got[0])
+	assert.Equal(t, &sqltypes.Result{Rows: acquired.Rows}, got[1])
+
+	// without wantfields, only the row is sent.
+	got = nil
+	err = l.StreamExecute(vc, nil, false, func(qr *sqltypes.Result) error {
+		got = append(got, qr)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*sqltypes.Result{{Rows: acquired.Rows}}, got)
+}
+
+func TestLockStreamExecuteError(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1")
+
+	// the code of the callback error is preserved.
+	vc := &loggingVCursor{results: []*sqltypes.Result{result}}
+	err = l.StreamExecute(vc, nil, true, func(qr *sqltypes.Result) error {
+		return vterrors.New(vtrpc.Code_RESOURCE_EXHAUSTED, "result too large")
+	})
+	require.EqualError(t, err, "Lock.StreamExecute: result too large")
+	assert.Equal(t, vtrpc.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+
+	vc = &loggingVCursor{results: []*sqltypes.Result{result}}
+	err = l.StreamExecute(vc, nil, false, func(qr *sqltypes.Result) error {
+		return vterrors.New(vtrpc.Code_CANCELED, "client gone")
+	})
+	require.EqualError(t, err, "Lock.StreamExecute: client gone")
+	assert.Equal(t, vtrpc.Code_CANCELED, vterrors.Code(err))
+
+	// and so is the code of the lock query error.
+	vc = &loggingVCursor{resultErr: vterrors.New(vtrpc.Code_DEADLINE_EXCEEDED, "lock timeout")}
+	err = l.StreamExecute(vc, nil, true, func(qr *sqltypes.Result) error {
+		t.Fatal("unexpected callback")
+		return nil
+	})
+	require.EqualError(t, err, "Lock.StreamExecute: lock timeout")
+	assert.Equal(t, vtrpc.Code_DEADLINE_EXCEEDED, vterrors.Code(err))
+}
+
+func TestLockSessionDestination(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lock name', 10)", "int64"), "1")
+
+	// the lock follows the destination the session is pinned to.
+	vc := &loggingVCursor{
+		results:            []*sqltypes.Result{result},
+		shardForKsid:       []string{"80-"},
+		sessionKeyspace:    "ks",
+		sessionDestination: key.DestinationKeyspaceID{0x90},
+	}
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(90)",
+		"ExecuteLock ks.80-: select get_lock('lock name', 10) from dual {}",
+	})
+
+	// an explicit shard of the primitive must match the session destination.
+	l.TargetDestination = key.DestinationShard("-80")
+	vc = &loggingVCursor{sessionKeyspace: "ks", sessionDestination: key.DestinationShard("80-")}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "lock destination conflicts with the session target destination DestinationShard(80-) (keyspace: ks, destination: DestinationShard(-80))")
+	assert.Equal(t, vtrpc.Code_INVALID_ARGUMENT, vterrors.Code(err))
+	vc.ExpectLog(t, nil)
+
+	// and so must the keyspace.
+	vc = &loggingVCursor{sessionKeyspace: "other", sessionDestination: key.DestinationShard("-80")}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "lock keyspace conflicts with the session target keyspace other (keyspace: ks, destination: DestinationShard(-80))")
+}
+
+func TestLockVindex(t *testing.T) {
+	vindex, _ := vindexes.NewHash("hash", nil)
+	ks := &vindexes.Keyspace{Name: "ks", Sharded: true}
+	tenant := sqltypes.PlanValue{Key: "tenant"}
+	_, err := NewLock(ks, key.DestinationKeyspaceID{0}, "select get_lock('tenant', 10) from dual", WithVindex(vindex.(vindexes.SingleColumn), tenant))
+	require.EqualError(t, err, "lock primitive cannot have both a target destination and a vindex")
+
+	l, err := NewLock(ks, nil, "select get_lock('tenant', 10) from dual", WithVindex(vindex.(vindexes.SingleColumn), tenant))
+	require.NoError(t, err)
+	assert.Equal(t, "hash", l.description().Other["Vindex"])
+
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('tenant', 10)", "int64"), "1")
+	execute := func(id int64, ksid string) {
+		t.Helper()
+		vc := &loggingVCursor{
+			results: []*sqltypes.Result{result},
+			// the session destination is ignored.
+			sessionKeyspace:    "ks",
+			sessionDestination: key.DestinationShard("20-"),
+		}
+		_, err := l.Execute(vc, map[string]*querypb.BindVariable{"tenant": sqltypes.Int64BindVariable(id)}, false)
+		require.NoError(t, err)
+		vc.ExpectLog(t, []string{
+			fmt.Sprintf(`ResolveDestinations ks [type:INT64 value:"%d" ] Destinations:DestinationKeyspaceID(%s)`, id, ksid),
+			fmt.Sprintf(`ExecuteLock ks.-20: select get_lock('tenant', 10) from dual {tenant: type:INT64 value:"%d" }`, id),
+		})
+	}
+	// the same id always resolves to the same keyspace id.
+	execute(1, "166b40b44aba4bd6")
+	execute(1, "166b40b44aba4bd6")
+	execute(2, "06e7ea22ce92708f")
+
+	_, err = l.Execute(&loggingVCursor{}, nil, false)
+	require.EqualError(t, err, "invalid lock vindex value: missing bind var tenant")
+}
+
+func TestLockDestinationRendering(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationShard("-80"), "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	out, err := json.Marshal(PrimitiveToPlanDescription(l))
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"TargetDestination":"Shard(-80)"`)
+
+	// routing errors show the destination and the shards it resolves to.
+	l.TargetDestination = key.DestinationAllShards{}
+	vc := &loggingVCursor{shards: []string{"-80", "80-"}}
+	_, err = l.Execute(vc, nil, false)
+	require.EqualError(t, err, "lock query cannot be routed to vttablet: resolves to shards [ks/-80 ks/80-] (keyspace: ks, destination: DestinationAllShards())")
+	assert.Equal(t, vtrpc.Code_FAILED_PRECONDITION, vterrors.Code(err))
+}
+
+func TestLockDryRun(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock(:name, 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Key: "name"}}))
+	require.NoError(t, err)
+	bv := map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable("dry run lock")}
+
+	vc := &loggingVCursor{dryRun: true}
+	qr, err := l.Execute(vc, bv, false)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{}, qr)
+	// the lock query is routed, but never sent.
+	vc.ExpectLog(t, []string{"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)"})
+	assert.Empty(t, vc.advisoryLocks)
+
+	// validation errors are still reported.
+	_, err = l.Execute(vc, map[string]*querypb.BindVariable{}, false)
+	require.EqualError(t, err, "missing bind var name for the lock name")
+
+	l.TargetDestination = key.DestinationAllShards{}
+	vc = &loggingVCursor{dryRun: true, shards: []string{"-80", "80-"}}
+	_, err = l.Execute(vc, bv, false)
+	require.EqualError(t, err, "lock query cannot be routed to vttablet: resolves to shards [ks/-80 ks/80-] (keyspace: ks, destination: DestinationAllShards())")
+}
+
+func TestLockEvents(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('a', 10), release_lock('b') from dual",
+		WithLockFuncs(
+			LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("a")}, Column: 0},
+			LockFunc{Type: ReleaseLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("b")}, Column: 1},
+		))
+	require.NoError(t, err)
+
+	vc := &loggingVCursor{results: []*sqltypes.Result{
+		sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('a', 10)|release_lock('b')", "int64|int64"), "1|null"),
+	}}
+	defer lockWaits.releasedAll(vc)
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, []LockEvent{
+		{Type: GetLock, Name: "a", Outcome: "1"},
+		{Type: ReleaseLock, Name: "b", Outcome: "NULL"},
+	}, vc.lockEvents)
+
+	// the hook is also called when the lock query fails.
+	lockErr := errors.New("connection reset")
+	vc = &loggingVCursor{resultErr: lockErr}
+	_, err = l.Execute(vc, nil, false)
+	require.Error(t, err)
+	assert.Equal(t, []LockEvent{
+		{Type: GetLock, Name: "a", Err: lockErr},
+		{Type: ReleaseLock, Name: "b", Err: lockErr},
+	}, vc.lockEvents)
+}
+
+func TestCheckReservedConn(t *testing.T) {
+	defer func() { testReservedConnDisabled = false }()
+
+	send := &Send{Keyspace: &vindexes.Keyspace{Name: "ks"}, TargetDestination: key.DestinationAllShards{}, Query: "insert into t values (1)", IsDML: true}
+	lock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	route := NewRoute(SelectUnsharded, &vindexes.Keyspace{Name: "ks"}, "select id from t for update", "select id from t where 1 != 1")
+
+	// the lock is only reached after the insert.
+	withLock := &Concatenate{Sources: []Primitive{send, lock}}
+	withForUpdate := &Concatenate{Sources: []Primitive{send, &ForUpdate{Input: route}}}
+	withoutReservedConn := &Concatenate{Sources: []Primitive{send, route}}
+
+	vc := &loggingVCursor{}
+	require.NoError(t, CheckReservedConn(vc, withLock))
+	require.NoError(t, CheckReservedConn(vc, withForUpdate))
+
+	testReservedConnDisabled = true
+	err = CheckReservedConn(vc, withLock)
+	require.EqualError(t, err, "the Lock primitive of the plan requires reserved connections, which are disabled")
+	assert.Equal(t, vtrpc.Code_FAILED_PRECONDITION, vterrors.Code(err))
+	err = CheckReservedConn(vc, withForUpdate)
+	require.EqualError(t, err, "the ForUpdate primitive of the plan requires reserved connections, which are disabled")
+	require.NoError(t, CheckReservedConn(vc, withoutReservedConn))
+	// nothing is executed by the check.
+	vc.ExpectLog(t, nil)
+}
+
+func TestLockBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	breaker := NewLockBreaker(2, time.Minute, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('busy lock', 10) from dual",
+		WithBreaker(breaker), WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("busy lock")}}))
+	require.NoError(t, err)
+
+	fields := sqltypes.MakeTestFields("get_lock", "int64")
+	busy := sqltypes.MakeTestResult(fields, "0")
+	vc := &loggingVCursor{results: []*sqltypes.Result{busy, busy, sqltypes.MakeTestResult(fields, "1")}}
+	defer lockWaits.releasedAll(vc)
+	executeLock := func() *sqltypes.Result {
+		t.Helper()
+		qr, err := l.Execute(vc, nil, false)
+		require.NoError(t, err)
+		return qr
+	}
+	lockQueries := func() int {
+		n := 0
+		for _, entry := range vc.log {
+			if strings.HasPrefix(entry, "ExecuteLock") {
+				n++
+			}
+		}
+		return n
+	}
+
+	// the breaker opens after two busy acquisitions.
+	assert.Equal(t, busy, executeLock())
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, busy, executeLock())
+	assert.Equal(t, 2, lockQueries())
+
+	// while it is open, the lock query is not sent.
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, busy, executeLock())
+	assert.Equal(t, 2, lockQueries())
+	// the skipped acquisition is reported like the others.
+	assert.Equal(t, LockEvent{Type: GetLock, Name: "busy lock", Outcome: "0"}, vc.lockEvents[2])
+
+	// the result of a skipped lock query is named like the one of a lock query sent.
+	aliased := l.Clone()
+	aliased.ColumnAlias = "locked"
+	qr, err := aliased.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "locked", qr.Fields[0].Name)
+	assert.Equal(t, 2, lockQueries())
+
+	// it closes after the cooldown.
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, "1", executeLock().Rows[0][0].ToString())
+	assert.Equal(t, 3, lockQueries())
+	assert.EqualValues(t, 1, vc.AdvisoryLockCount("busy lock"))
+}
+
+func TestLockBreakerWindow(t *testing.T) {
+	now := time.Unix(0, 0)
+	breaker := NewLockBreaker(2, time.Minute, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+
+	// busy acquisitions further apart than the window do not open the breaker.
+	breaker.record("lock name", true)
+	now = now.Add(2 * time.Minute)
+	breaker.record("lock name", true)
+	assert.True(t, breaker.allow("lock name"))
+	breaker.record("lock name", true)
+	assert.False(t, breaker.allow("lock name"))
+
+	// the breakers of the other locks are not affected.
+	assert.True(t, breaker.allow("other lock"))
+}
+
+func TestLockHoldMetrics(t *testing.T) {
+	now := time.Unix(0, 0)
+	defer func(f func() time.Time) { lockHolds.now = f }(lockHolds.now)
+	lockHolds.now = func() time.Time { return now }
+
+	name := sqltypes.PlanValue{Value: sqltypes.NewVarChar("held lock")}
+	getLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('held lock', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: name}))
+	require.NoError(t, err)
+	releaseLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select release_lock('held lock') from dual",
+		WithLockFuncs(LockFunc{Type: ReleaseLock, Name: name}))
+	require.NoError(t, err)
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('held lock', 10)", "int64"), "1")
+	released := sqltypes.MakeTestResult(sqltypes.MakeTestFields("release_lock('held lock')", "int64"), "1")
+
+	releasedCount := lockHolds.holdTimes.Counts()["Released"]
+	closedCount := lockHolds.holdTimes.Counts()["SessionClosed"]
+	vc := &loggingVCursor{results: []*sqltypes.Result{acquired, acquired, released, released, acquired}}
+	defer lockWaits.releasedAll(vc)
+
+	// the lock is acquired twice, the connection is held until it is released twice.
+	_, err = getLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	now = now.Add(time.Second)
+	_, err = getLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	now = now.Add(time.Second)
+	_, err = releaseLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, releasedCount, lockHolds.holdTimes.Counts()["Released"])
+	now = now.Add(time.Second)
+	_, err = releaseLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, releasedCount+1, lockHolds.holdTimes.Counts()["Released"])
+
+	// a session closed with locks held records a sample too.
+	_, err = getLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	now = now.Add(5 * time.Second)
+	AllLocksReleased(vc.SessionKey())
+	assert.EqualValues(t, closedCount+1, lockHolds.holdTimes.Counts()["SessionClosed"])
+	// the session is only counted once.
+	AllLocksReleased(vc.SessionKey())
+	assert.EqualValues(t, closedCount+1, lockHolds.holdTimes.Counts()["SessionClosed"])
+}
+
+func TestLockConnectionLost(t *testing.T) {
+	getLock, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lost lock', 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("lost lock")}}))
+	require.NoError(t, err)
+	vc := &loggingVCursor{results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('lost lock', 10)", "int64"), "1")}}
+	defer lockWaits.releasedAll(vc)
+	_, err = getLock.Execute(vc, nil, false)
+	require.NoError(t, err)
+	assert.True(t, lockWaits.heldByOther("other session", vc.lockTarget, "lost lock"))
+
+	lostCount := lockHolds.holdTimes.Counts()["ConnectionLost"]
+	LockConnectionLost(vc.SessionKey())
+	assert.False(t, lockWaits.heldByOther("other session", vc.lockTarget, "lost lock"))
+	assert.EqualValues(t, lostCount+1, lockHolds.holdTimes.Counts()["ConnectionLost"])
+}
+
+func TestLockMaxLockWait(t *testing.T) {
+	defer func() { testMaxLockWait = 0 }()
+	testMaxLockWait = 5 * time.Minute
+
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('long lock', 3600) from dual")
+	require.NoError(t, err)
+	vc := &loggingVCursor{}
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+	// the hour requested by the client is lowered to the ceiling.
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		"ExecuteLock ks.-20: select get_lock('long lock', 300) from dual {}",
+	})
+	// the plan is not modified.
+	assert.Equal(t, "select get_lock('long lock', 3600) from dual", l.Query)
+	assert.Zero(t, l.Timeout)
+
+	tcases := []struct {
+		query, want string
+	}{{
+		query: "select get_lock('lock name', -1) from dual",
+		want:  "select get_lock('lock name', 300) from dual",
+	}, {
+		query: "select get_lock('lock name', -1.5) from dual",
+		want:  "select get_lock('lock name', 300) from dual",
+	}, {
+		query: "select get_lock('lock name', 300.5) from dual",
+		want:  "select get_lock('lock name', 300) from dual",
+	}, {
+		query: "select get_lock('lock name', 10), get_lock('other', 600) from dual",
+		want:  "select get_lock('lock name', 10), get_lock('other', 300) from dual",
+	}, {
+		// shorter timeouts, and timeouts that are not literals, are kept.
+		query: "select get_lock('lock name', 10) from dual",
+		want:  "select get_lock('lock name', 10) from dual",
+	}, {
+		query: "select get_lock('lock name', :timeout) from dual",
+		want:  "select get_lock('lock name', :timeout) from dual",
+	}}
+	for _, tcase := range tcases {
+		got, err := clampLockTimeout(tcase.query, 5*time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, tcase.want, got, tcase.query)
+	}
+
+	// the lock query never waits longer than the ceiling.
+	assert.Equal(t, 5*time.Minute, l.withMaxLockWait(5*time.Minute).Timeout)
+	short := l.WithTimeout(time.Minute)
+	assert.Same(t, short, short.withMaxLockWait(5*time.Minute))
+}
+
+// commentingVCursor adds a comment to the lock queries, like the margin comments of vtgate.
+type commentingVCursor struct {
+	*loggingVCursor
+}
+
+func (vc *commentingVCursor) ExecuteLock(rs *srvtopo.ResolvedShard, query *querypb.BoundQuery) (*sqltypes.Result, error) {
+	query.Sql += " /* comment */"
+	return vc.loggingVCursor.ExecuteLock(rs, query)
+}
+
+func TestLockReusedBoundQuery(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock(:name, 10) from dual",
+		WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Key: "name"}}))
+	require.NoError(t, err)
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(:name, 10)", "int64"), "1")
+	vc := &commentingVCursor{loggingVCursor: &loggingVCursor{results: []*sqltypes.Result{acquired, acquired}}}
+	defer lockWaits.releasedAll(vc.loggingVCursor)
+
+	// each lock query is sent with its own bind variables, and without the changes
+	// the vcursor made to the previous one.
+	_, err = l.Execute(vc, map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable("a")}, false)
+	require.NoError(t, err)
+	_, err = l.Execute(vc, map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable("b")}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteLock ks.-20: select get_lock(:name, 10) from dual /* comment */ {name: type:VARBINARY value:"a" }`,
+		"SetAdvisoryLockCount a 1",
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteLock ks.-20: select get_lock(:name, 10) from dual /* comment */ {name: type:VARBINARY value:"b" }`,
+		"SetAdvisoryLockCount b 1",
+	})
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"sync"
+	"time"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// lockWaits is the wait-for graph of the advisory locks acquired through this vtgate.
+var lockWaits = newLockWaitGraph()
+
+// LockReleased records that the session released the lock it held on the target outside
+// of the Lock primitive, e.g. when the locks acquired during a transaction are released on commit.
+func LockReleased(session interface{}, target *querypb.Target, name string) {
+	lockWaits.released(session, target, name)
+}
+
+// LocksReacquired records that the session acquired the locks again on the target outside of
+// the Lock primitive, after the connection holding them was lost.
+func LocksReacquired(session interface{}, target *querypb.Target, names []string) {
+	for _, name := range names {
+		lockWaits.acquired(session, target, name)
+	}
+	if len(names) != 0 {
+		lockHolds.started(session)
+	}
+}
+
+// AllLocksReleased records that the session released all its locks outside of the Lock
+// primitive, e.g. when the session is closed.
+func AllLocksReleased(session interface{}) {
+	lockWaits.releasedAll(session)
+	lockHolds.ended(session, "SessionClosed")
+}
+
+// LockConnectionLost records that the session lost all its locks along with the connection holding them.
+func LockConnectionLost(session interface{}) {
+	lockWaits.releasedAll(session)
+	lockHolds.ended(session, "ConnectionLost")
+}
+
+// lockKey identifies an advisory lock: the locks of different shards are different locks,
+// even when they have the same name.
+type lockKey struct {
+	keyspace, shard, name string
+}
+
+func newLockKey(target *querypb.Target, name string) lockKey {
+	if target == nil {
+		return lockKey{name: name}
+	}
+	return lockKey{keyspace: target.Keyspace, shard: target.Shard, name: name}
+}
+
+// lockWaitGraph keeps track of the sessions holding advisory locks and of the
+// sessions waiting for them. MySQL only sees the locks of a single server, while
+// vtgate sees all of them, so it can detect deadlocks MySQL would not.
+// The sessions are identified by VCursor.SessionKey. A nil session holds no lock,
+// so it is not recorded.
+type lockWaitGraph struct {
+	now func() time.Time
+
+	mu sync.Mutex
+	// holders is the session holding each lock.
+	holders map[lockKey]interface{}
+	// since is the time each lock was acquired by its holder.
+	since map[lockKey]time.Time
+	// waiting is the locks each session is waiting for.
+	waiting map[interface{}][]lockKey
+}
+
+func newLockWaitGraph() *lockWaitGraph {
+	return &lockWaitGraph{
+		now:     time.Now,
+		holders: make(map[lockKey]interface{}),
+		since:   make(map[lockKey]time.Time),
+		waiting: make(map[interface{}][]lockKey),
+	}
+}
+
+// wait records that the session is about to wait for the locks of the target.
+// It returns an ABORTED error, without recording anything, if waiting would deadlock.
+// Every successful call must be followed by a call to done.
+func (g *lockWaitGraph) wait(session interface{}, target *querypb.Target, names []string) error {
+	if session == nil {
+		// the session holds no lock: no other session can be waiting for it.
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	keys := make([]lockKey, len(names))
+	for i, name := range names {
+		keys[i] = newLockKey(target, name)
+		holder := g.holders[keys[i]]
+		if holder == session {
+			// advisory locks are re-entrant.
+			continue
+		}
+		if g.waitsFor(holder, session, map[interface{}]bool{}) {
+			return vterrors.Errorf(vtrpc.Code_ABORTED, "deadlock found when trying to get lock '%s'; try releasing locks and restarting lock acquisition", name)
+		}
+	}
+	g.waiting[session] = keys
+	return nil
+}
+
+// waitsFor returns true if the holder is the session, or waits for a lock
+// that is held by the session, directly or through other sessions.
+func (g *lockWaitGraph) waitsFor(holder, session interface{}, visited map[interface{}]bool) bool {
+	if holder == nil || visited[holder] {
+		return false
+	}
+	if holder == session {
+		return true
+	}
+	visited[holder] = true
+	for _, key := range g.waiting[holder] {
+		if next, ok := g.holders[key]; ok && next != holder && g.waitsFor(next, session, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// heldByOther returns true if the lock of the target is held by another session than the given one.
+func (g *lockWaitGraph) heldByOther(session interface{}, target *querypb.Target, name string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	holder, ok := g.holders[newLockKey(target, name)]
+	return ok && holder != session
+}
+
+// done records that the session is not waiting anymore.
+func (g *lockWaitGraph) done(session interface{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.waiting, session)
+}
+
+// acquired records that the session holds the lock of the target.
+func (g *lockWaitGraph) acquired(session interface{}, target *querypb.Target, name string) {
+	if session == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := newLockKey(target, name)
+	if g.holders[key] != session {
+		g.since[key] = g.now()
+	}
+	g.holders[key] = session
+}
+
+// acquiredAt returns the time the session acquired the lock of the target, if it is known to hold it.
+func (g *lockWaitGraph) acquiredAt(session interface{}, target *querypb.Target, name string) (time.Time, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := newLockKey(target, name)
+	if holder, ok := g.holders[key]; !ok || holder != session {
+		return time.Time{}, false
+	}
+	return g.since[key], true
+}
+
+// released records that the session does not hold the lock of the target anymore.
+func (g *lockWaitGraph) released(session interface{}, target *querypb.Target, name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	key := newLockKey(target, name)
+	if holder, ok := g.holders[key]; ok && holder == session {
+		delete(g.holders, key)
+		delete(g.since, key)
+	}
+}
+
+// releasedAll records that the session does not hold any lock anymore.
+func (g *lockWaitGraph) releasedAll(session interface{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for key, holder := range g.holders {
+		if holder == session {
+			delete(g.holders, key)
+			delete(g.since, key)
+		}
+	}
+}
@@ -45,7 +45,7 @@ type MemorySort struct {
 }
 
 // RouteType returns a description of the query routing type used by the primitive.
-func (ms *MemorySort) RouteType() string {
+func (ms *MemorySort) RouteType() RouteType {
 	return ms.Input.RouteType()
 }
 
@@ -59,6 +59,23 @@ func (ms *MemorySort) GetTableName() string {
 	return ms.Input.GetTableName()
 }
 
+// HasSideEffects implements the Primitive interface
+func (ms *MemorySort) HasSideEffects() bool {
+	return inputsHaveSideEffects(ms)
+}
+
+// EstimatedMemory implements the Primitive interface
+// It holds the rows it sorts, at most UpperLimit of them when it is a literal.
+func (ms *MemorySort) EstimatedMemory() int64 {
+	rows := bufferedRows
+	if !ms.UpperLimit.Value.IsNull() {
+		if limit, err := ms.UpperLimit.Value.ToInt64(); err == nil && limit < rows {
+			rows = limit
+		}
+	}
+	return primitiveMemory + rows*rowMemory + inputsMemory(ms)
+}
+
 // SetTruncateColumnCount sets the truncate column count.
 func (ms *MemorySort) SetTruncateColumnCount(count int) {
 	ms.TruncateColumnCount = count
@@ -151,6 +168,13 @@ func (ms *MemorySort) Inputs() []Primitive {
 	return []Primitive{ms.Input}
 }
 
+// withInputs implements the analyzable interface
+func (ms *MemorySort) withInputs(inputs []Primitive) Primitive {
+	cp := *ms
+	cp.Input = inputs[0]
+	return &cp
+}
+
 func (ms *MemorySort) NeedsTransaction() bool {
 	return ms.Input.NeedsTransaction()
 }
@@ -56,7 +56,7 @@ type MergeSort struct {
 }
 
 // RouteType satisfies Primitive.
-func (ms *MergeSort) RouteType() string { return "MergeSort" }
+func (ms *MergeSort) RouteType() RouteType { return RouteTypeMergeSort }
 
 // GetKeyspaceName satisfies Primitive.
 func (ms *MergeSort) GetKeyspaceName() string { return "" }
@@ -64,6 +64,12 @@ func (ms *MergeSort) GetKeyspaceName() string { return "" }
 // GetTableName satisfies Primitive.
 func (ms *MergeSort) GetTableName() string { return "" }
 
+// HasSideEffects satisfies Primitive.
+func (ms *MergeSort) HasSideEffects() bool { return false }
+
+// EstimatedMemory satisfies Primitive.
+func (ms *MergeSort) EstimatedMemory() int64 { return primitiveMemory }
+
 // Execute is not supported.
 func (ms *MergeSort) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "Execute is not supported")
@@ -53,8 +53,8 @@ func (v *OnlineDDL) description() PrimitiveDescription {
 }
 
 // RouteType implements the Primitive interface
-func (v *OnlineDDL) RouteType() string {
-	return "OnlineDDL"
+func (v *OnlineDDL) RouteType() RouteType {
+	return RouteTypeOnlineDDL
 }
 
 // GetKeyspaceName implements the Primitive interface
@@ -67,6 +67,16 @@ func (v *OnlineDDL) GetTableName() string {
 	return v.DDL.GetTable().Name.String()
 }
 
+// HasSideEffects implements the Primitive interface
+func (v *OnlineDDL) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory implements the Primitive interface
+func (v *OnlineDDL) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // Execute implements the Primitive interface
 func (v *OnlineDDL) Execute(vcursor VCursor, bindVars map[string]*query.BindVariable, wantfields bool) (result *sqltypes.Result, err error) {
 	onlineDDL, err := schema.NewOnlineDDL(v.GetKeyspaceName(), v.GetTableName(), v.SQL, v.Strategy, v.Options, "vtgate")
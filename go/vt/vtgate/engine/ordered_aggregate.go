@@ -131,7 +131,7 @@ func (code AggregateOpcode) MarshalJSON() ([]byte, error) {
 }
 
 // RouteType returns a description of the query routing type used by the primitive
-func (oa *OrderedAggregate) RouteType() string {
+func (oa *OrderedAggregate) RouteType() RouteType {
 	return oa.Input.RouteType()
 }
 
@@ -145,6 +145,17 @@ func (oa *OrderedAggregate) GetTableName() string {
 	return oa.Input.GetTableName()
 }
 
+// HasSideEffects implements the Primitive interface
+func (oa *OrderedAggregate) HasSideEffects() bool {
+	return inputsHaveSideEffects(oa)
+}
+
+// EstimatedMemory implements the Primitive interface
+// The input rows are ordered, so only the current group is held.
+func (oa *OrderedAggregate) EstimatedMemory() int64 {
+	return primitiveMemory + inputsMemory(oa)
+}
+
 // SetTruncateColumnCount sets the truncate column count.
 func (oa *OrderedAggregate) SetTruncateColumnCount(count int) {
 	oa.TruncateColumnCount = count
@@ -324,6 +335,13 @@ func (oa *OrderedAggregate) Inputs() []Primitive {
 	return []Primitive{oa.Input}
 }
 
+// withInputs implements the analyzable interface
+func (oa *OrderedAggregate) withInputs(inputs []Primitive) Primitive {
+	cp := *oa
+	cp.Input = inputs[0]
+	return &cp
+}
+
 func (oa *OrderedAggregate) NeedsTransaction() bool {
 	return oa.Input.NeedsTransaction()
 }
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"sort"
+	"strings"
+	"time"
 
 	"vitess.io/vitess/go/vt/key"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -40,6 +42,17 @@ type PrimitiveDescription struct {
 	TargetTabletType topodatapb.TabletType
 	Other            map[string]interface{}
 	Inputs           []PrimitiveDescription
+
+	// ActualRows and ActualTime are only set when the plan was executed in analyze mode.
+	ActualRows uint64
+	ActualTime time.Duration
+
+	// ReservedConn is set when the primitive needs a reserved connection.
+	ReservedConn bool
+
+	// Cost is the estimated cost of the primitive, without its inputs, see Coster.
+	// It is left out of the JSON representation, which only shows what the plan does.
+	Cost int
 }
 
 // MarshalJSON serializes the PlanDescription into a JSON representation.
@@ -63,8 +76,8 @@ func (pd PrimitiveDescription) MarshalJSON() ([]byte, error) {
 		}
 	}
 	if pd.TargetDestination != nil {
-		s := pd.TargetDestination.String()
-		dest := s[11:] // TODO: All these start with Destination. We should fix that instead if trimming it out here
+		// all the destinations of the key package start with Destination, which is redundant here.
+		dest := strings.TrimPrefix(pd.TargetDestination.String(), "Destination")
 
 		if err := marshalAdd(",", buf, "TargetDestination", dest); err != nil {
 			return nil, err
@@ -75,6 +88,19 @@ func (pd PrimitiveDescription) MarshalJSON() ([]byte, error) {
 			return nil, err
 		}
 	}
+	if pd.ActualRows != 0 || pd.ActualTime != 0 {
+		if err := marshalAdd(",", buf, "ActualRows", pd.ActualRows); err != nil {
+			return nil, err
+		}
+		if err := marshalAdd(",", buf, "ActualTime", pd.ActualTime.String()); err != nil {
+			return nil, err
+		}
+	}
+	if pd.ReservedConn {
+		if err := marshalAdd(",", buf, "ReservedConn", true); err != nil {
+			return nil, err
+		}
+	}
 	err := addMap(pd.Other, buf)
 	if err != nil {
 		return nil, err
@@ -122,6 +148,10 @@ func marshalAdd(prepend string, buf *bytes.Buffer, name string, obj interface{})
 //PrimitiveToPlanDescription transforms a primitive tree into a corresponding PlanDescription tree
 func PrimitiveToPlanDescription(in Primitive) PrimitiveDescription {
 	this := in.description()
+	this.ReservedConn = needsReservedConn(in)
+	if c, ok := in.(Coster); ok {
+		this.Cost = c.Cost()
+	}
 
 	for _, input := range in.Inputs() {
 		this.Inputs = append(this.Inputs, PrimitiveToPlanDescription(input))
@@ -0,0 +1,306 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	planpb "vitess.io/vitess/go/vt/proto/plan"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// DescriptionToProto converts the PrimitiveDescription tree into its protobuf representation.
+func DescriptionToProto(pd PrimitiveDescription) (*planpb.PrimitiveDescription, error) {
+	out := &planpb.PrimitiveDescription{
+		OperatorType:     pd.OperatorType,
+		Variant:          pd.Variant,
+		TargetTabletType: pd.TargetTabletType,
+		ActualRows:       pd.ActualRows,
+		ActualTime:       int64(pd.ActualTime),
+		ReservedConn:     pd.ReservedConn,
+		Cost:             int64(pd.Cost),
+	}
+	if pd.Keyspace != nil {
+		out.Keyspace = &planpb.Keyspace{
+			Name:    pd.Keyspace.Name,
+			Sharded: pd.Keyspace.Sharded,
+		}
+	}
+	if pd.TargetDestination != nil {
+		dest, err := destinationToProto(pd.TargetDestination)
+		if err != nil {
+			return nil, err
+		}
+		out.TargetDestination = dest
+	}
+	if len(pd.Other) > 0 {
+		out.Other = make(map[string]*planpb.Value, len(pd.Other))
+		for k, v := range pd.Other {
+			value, err := valueToProto(v)
+			if err != nil {
+				return nil, vterrors.Wrapf(err, "cannot serialize %s of %s", k, pd.OperatorType)
+			}
+			out.Other[k] = value
+		}
+	}
+	for _, input := range pd.Inputs {
+		in, err := DescriptionToProto(input)
+		if err != nil {
+			return nil, err
+		}
+		out.Inputs = append(out.Inputs, in)
+	}
+	return out, nil
+}
+
+// DescriptionFromProto converts the protobuf representation back into a PrimitiveDescription tree.
+// The values of Other come back as nil, string, int64, uint64, float64, bool, []interface{}
+// or map[string]interface{}.
+func DescriptionFromProto(in *planpb.PrimitiveDescription) (PrimitiveDescription, error) {
+	pd := PrimitiveDescription{
+		OperatorType:     in.OperatorType,
+		Variant:          in.Variant,
+		TargetTabletType: in.TargetTabletType,
+		ActualRows:       in.ActualRows,
+		ActualTime:       time.Duration(in.ActualTime),
+		ReservedConn:     in.ReservedConn,
+		Cost:             int(in.Cost),
+		Inputs:           make([]PrimitiveDescription, 0, len(in.Inputs)),
+	}
+	if in.Keyspace != nil {
+		pd.Keyspace = &vindexes.Keyspace{
+			Name:    in.Keyspace.Name,
+			Sharded: in.Keyspace.Sharded,
+		}
+	}
+	if in.TargetDestination != nil {
+		dest, err := destinationFromProto(in.TargetDestination)
+		if err != nil {
+			return PrimitiveDescription{}, err
+		}
+		pd.TargetDestination = dest
+	}
+	if len(in.Other) > 0 {
+		pd.Other = make(map[string]interface{}, len(in.Other))
+		for k, v := range in.Other {
+			pd.Other[k] = valueFromProto(v)
+		}
+	}
+	for _, input := range in.Inputs {
+		desc, err := DescriptionFromProto(input)
+		if err != nil {
+			return PrimitiveDescription{}, err
+		}
+		pd.Inputs = append(pd.Inputs, desc)
+	}
+	return pd, nil
+}
+
+// valueToProto converts a value of PrimitiveDescription.Other into its protobuf representation.
+// The values of other types than the basic ones, e.g. structs, are converted through their JSON encoding.
+func valueToProto(v interface{}) (*planpb.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return &planpb.Value{}, nil
+	case string:
+		return &planpb.Value{Kind: &planpb.Value_StringValue{StringValue: v}}, nil
+	case bool:
+		return &planpb.Value{Kind: &planpb.Value_BoolValue{BoolValue: v}}, nil
+	case int:
+		return &planpb.Value{Kind: &planpb.Value_IntValue{IntValue: int64(v)}}, nil
+	case int8:
+		return &planpb.Value{Kind: &planpb.Value_IntValue{IntValue: int64(v)}}, nil
+	case int16:
+		return &planpb.Value{Kind: &planpb.Value_IntValue{IntValue: int64(v)}}, nil
+	case int32:
+		return &planpb.Value{Kind: &planpb.Value_IntValue{IntValue: int64(v)}}, nil
+	case int64:
+		return &planpb.Value{Kind: &planpb.Value_IntValue{IntValue: v}}, nil
+	case uint:
+		return &planpb.Value{Kind: &planpb.Value_UintValue{UintValue: uint64(v)}}, nil
+	case uint8:
+		return &planpb.Value{Kind: &planpb.Value_UintValue{UintValue: uint64(v)}}, nil
+	case uint16:
+		return &planpb.Value{Kind: &planpb.Value_UintValue{UintValue: uint64(v)}}, nil
+	case uint32:
+		return &planpb.Value{Kind: &planpb.Value_UintValue{UintValue: uint64(v)}}, nil
+	case uint64:
+		return &planpb.Value{Kind: &planpb.Value_UintValue{UintValue: v}}, nil
+	case float32:
+		return &planpb.Value{Kind: &planpb.Value_FloatValue{FloatValue: float64(v)}}, nil
+	case float64:
+		return &planpb.Value{Kind: &planpb.Value_FloatValue{FloatValue: v}}, nil
+	case json.Number:
+		return numberToProto(v), nil
+	case []string:
+		list := &planpb.ListValue{Values: make([]*planpb.Value, 0, len(v))}
+		for _, s := range v {
+			list.Values = append(list.Values, &planpb.Value{Kind: &planpb.Value_StringValue{StringValue: s}})
+		}
+		return &planpb.Value{Kind: &planpb.Value_ListValue{ListValue: list}}, nil
+	case []interface{}:
+		list := &planpb.ListValue{Values: make([]*planpb.Value, 0, len(v))}
+		for _, e := range v {
+			value, err := valueToProto(e)
+			if err != nil {
+				return nil, err
+			}
+			list.Values = append(list.Values, value)
+		}
+		return &planpb.Value{Kind: &planpb.Value_ListValue{ListValue: list}}, nil
+	case map[string]interface{}:
+		fields := make(map[string]*planpb.Value, len(v))
+		for k, e := range v {
+			value, err := valueToProto(e)
+			if err != nil {
+				return nil, err
+			}
+			fields[k] = value
+		}
+		return &planpb.Value{Kind: &planpb.Value_MapValue{MapValue: &planpb.MapValue{Fields: fields}}}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	// the numbers must keep their type: they would all be decoded as float64 otherwise.
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	return valueToProto(decoded)
+}
+
+// numberToProto converts a JSON number into an integer value if it is one, a float value otherwise.
+func numberToProto(n json.Number) *planpb.Value {
+	if i, err := n.Int64(); err == nil {
+		return &planpb.Value{Kind: &planpb.Value_IntValue{IntValue: i}}
+	}
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return &planpb.Value{Kind: &planpb.Value_UintValue{UintValue: u}}
+	}
+	// a JSON number is always a valid float.
+	f, _ := n.Float64()
+	return &planpb.Value{Kind: &planpb.Value_FloatValue{FloatValue: f}}
+}
+
+// valueFromProto converts the protobuf representation of a value of PrimitiveDescription.Other back.
+func valueFromProto(v *planpb.Value) interface{} {
+	switch kind := v.GetKind().(type) {
+	case *planpb.Value_StringValue:
+		return kind.StringValue
+	case *planpb.Value_IntValue:
+		return kind.IntValue
+	case *planpb.Value_UintValue:
+		return kind.UintValue
+	case *planpb.Value_FloatValue:
+		return kind.FloatValue
+	case *planpb.Value_BoolValue:
+		return kind.BoolValue
+	case *planpb.Value_ListValue:
+		list := make([]interface{}, 0, len(kind.ListValue.GetValues()))
+		for _, e := range kind.ListValue.GetValues() {
+			list = append(list, valueFromProto(e))
+		}
+		return list
+	case *planpb.Value_MapValue:
+		fields := make(map[string]interface{}, len(kind.MapValue.GetFields()))
+		for k, e := range kind.MapValue.GetFields() {
+			fields[k] = valueFromProto(e)
+		}
+		return fields
+	}
+	return nil
+}
+
+func destinationToProto(dest key.Destination) (*planpb.Destination, error) {
+	switch d := dest.(type) {
+	case key.DestinationShard:
+		return &planpb.Destination{Type: planpb.Destination_SHARD, Shards: []string{string(d)}}, nil
+	case key.DestinationShards:
+		return &planpb.Destination{Type: planpb.Destination_SHARDS, Shards: d}, nil
+	case key.DestinationExactKeyRange:
+		return &planpb.Destination{Type: planpb.Destination_EXACT_KEY_RANGE, KeyRanges: []*topodatapb.KeyRange{d.KeyRange}}, nil
+	case key.DestinationExactKeyRanges:
+		return &planpb.Destination{Type: planpb.Destination_EXACT_KEY_RANGES, KeyRanges: d}, nil
+	case key.DestinationKeyRange:
+		return &planpb.Destination{Type: planpb.Destination_KEY_RANGE, KeyRanges: []*topodatapb.KeyRange{d.KeyRange}}, nil
+	case key.DestinationKeyRanges:
+		return &planpb.Destination{Type: planpb.Destination_KEY_RANGES, KeyRanges: d}, nil
+	case key.DestinationKeyspaceID:
+		return &planpb.Destination{Type: planpb.Destination_KEYSPACE_ID, KeyspaceIds: [][]byte{d}}, nil
+	case key.DestinationKeyspaceIDs:
+		return &planpb.Destination{Type: planpb.Destination_KEYSPACE_IDS, KeyspaceIds: d}, nil
+	case key.DestinationAnyShard:
+		return &planpb.Destination{Type: planpb.Destination_ANY_SHARD}, nil
+	case key.DestinationAllShards:
+		return &planpb.Destination{Type: planpb.Destination_ALL_SHARDS}, nil
+	case key.DestinationNone:
+		return &planpb.Destination{Type: planpb.Destination_NONE}, nil
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "unsupported destination: %v", dest)
+}
+
+func destinationFromProto(dest *planpb.Destination) (key.Destination, error) {
+	switch dest.Type {
+	case planpb.Destination_SHARD:
+		if len(dest.Shards) != 1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "expected exactly one shard: %v", dest)
+		}
+		return key.DestinationShard(dest.Shards[0]), nil
+	case planpb.Destination_SHARDS:
+		return key.DestinationShards(dest.Shards), nil
+	case planpb.Destination_EXACT_KEY_RANGE:
+		if len(dest.KeyRanges) != 1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "expected exactly one key range: %v", dest)
+		}
+		return key.DestinationExactKeyRange{KeyRange: dest.KeyRanges[0]}, nil
+	case planpb.Destination_EXACT_KEY_RANGES:
+		return key.DestinationExactKeyRanges(dest.KeyRanges), nil
+	case planpb.Destination_KEY_RANGE:
+		if len(dest.KeyRanges) != 1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "expected exactly one key range: %v", dest)
+		}
+		return key.DestinationKeyRange{KeyRange: dest.KeyRanges[0]}, nil
+	case planpb.Destination_KEY_RANGES:
+		return key.DestinationKeyRanges(dest.KeyRanges), nil
+	case planpb.Destination_KEYSPACE_ID:
+		if len(dest.KeyspaceIds) != 1 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "expected exactly one keyspace id: %v", dest)
+		}
+		return key.DestinationKeyspaceID(dest.KeyspaceIds[0]), nil
+	case planpb.Destination_KEYSPACE_IDS:
+		return key.DestinationKeyspaceIDs(dest.KeyspaceIds), nil
+	case planpb.Destination_ANY_SHARD:
+		return key.DestinationAnyShard{}, nil
+	case planpb.Destination_ALL_SHARDS:
+		return key.DestinationAllShards{}, nil
+	case planpb.Destination_NONE:
+		return key.DestinationNone{}, nil
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown destination type: %v", dest.Type)
+}
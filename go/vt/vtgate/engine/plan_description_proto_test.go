@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	planpb "vitess.io/vitess/go/vt/proto/plan"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestDescriptionProtoRoundTrip(t *testing.T) {
+	lock := &Lock{
+		Keyspace:          &vindexes.Keyspace{Name: "ks", Sharded: true},
+		TargetDestination: key.DestinationKeyspaceID{1, 2, 3},
+		Query:             "select get_lock('lock name', 10) from dual",
+	}
+	send := &Send{
+		Keyspace:          &vindexes.Keyspace{Name: "ks"},
+		TargetDestination: key.DestinationShards{"-80", "80-"},
+		Query:             "dummy_query",
+	}
+	in := PrimitiveToPlanDescription(&Concatenate{Sources: []Primitive{lock, send}})
+	in.ActualRows = 2
+	in.ActualTime = 3 * time.Millisecond
+	in.TargetTabletType = topodatapb.TabletType_MASTER
+
+	pb, err := DescriptionToProto(in)
+	require.NoError(t, err)
+
+	// the description has to survive going over the wire
+	b, err := proto.Marshal(pb)
+	require.NoError(t, err)
+	received := &planpb.PrimitiveDescription{}
+	require.NoError(t, proto.Unmarshal(b, received))
+
+	out, err := DescriptionFromProto(received)
+	require.NoError(t, err)
+	utils.MustMatch(t, in, out, "round trip did not preserve the description")
+	require.Equal(t, key.DestinationKeyspaceID{1, 2, 3}, out.Inputs[0].TargetDestination)
+	require.Equal(t, singleShardCost, out.Inputs[0].Cost)
+	require.Equal(t, multiShardCost, out.Inputs[1].Cost)
+}
+
+func TestDescriptionProtoOtherTypes(t *testing.T) {
+	type limits struct {
+		Max   int     `json:"max"`
+		Ratio float64 `json:"ratio"`
+	}
+	in := PrimitiveDescription{
+		OperatorType: "Lock",
+		Other: map[string]interface{}{
+			"Count": 3,
+			"Nested": map[string]interface{}{
+				"Names":  []string{"a", "b"},
+				"Values": []interface{}{uint64(18446744073709551615), 0.5, true, nil},
+			},
+			"Limits": limits{Max: 10, Ratio: 1.5},
+		},
+	}
+	pb, err := DescriptionToProto(in)
+	require.NoError(t, err)
+	b, err := proto.Marshal(pb)
+	require.NoError(t, err)
+	received := &planpb.PrimitiveDescription{}
+	require.NoError(t, proto.Unmarshal(b, received))
+
+	out, err := DescriptionFromProto(received)
+	require.NoError(t, err)
+	// the integers are not turned into floats on the way.
+	require.Equal(t, map[string]interface{}{
+		"Count": int64(3),
+		"Nested": map[string]interface{}{
+			"Names":  []interface{}{"a", "b"},
+			"Values": []interface{}{uint64(18446744073709551615), 0.5, true, nil},
+		},
+		"Limits": map[string]interface{}{"max": int64(10), "ratio": 1.5},
+	}, out.Other)
+}
+
+type unsupportedDestination struct {
+	key.DestinationNone
+}
+
+func TestDescriptionToProtoUnsupportedDestination(t *testing.T) {
+	_, err := DescriptionToProto(PrimitiveDescription{
+		OperatorType:      "Lock",
+		TargetDestination: unsupportedDestination{},
+	})
+	require.EqualError(t, err, "unsupported destination: DestinationNone()")
+}
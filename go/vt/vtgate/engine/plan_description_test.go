@@ -17,8 +17,12 @@ limitations under the License.
 package engine
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"vitess.io/vitess/go/test/utils"
 
 	"vitess.io/vitess/go/sqltypes"
@@ -89,6 +93,25 @@ func TestPlanDescriptionWithInputs(t *testing.T) {
 	mustMatch(t, expected, planDescription, "descriptions did not match")
 }
 
+type customDestination struct {
+	key.DestinationNone
+}
+
+func (customDestination) String() string {
+	return "Custom"
+}
+
+func TestPlanDescriptionDestination(t *testing.T) {
+	out, err := json.Marshal(PrimitiveDescription{OperatorType: "Lock", TargetDestination: key.DestinationShard("-80")})
+	require.NoError(t, err)
+	assert.Equal(t, `{"OperatorType":"Lock","TargetDestination":"Shard(-80)"}`, string(out))
+
+	// destinations not defined by the key package are rendered as is.
+	out, err = json.Marshal(PrimitiveDescription{OperatorType: "Lock", TargetDestination: customDestination{}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"OperatorType":"Lock","TargetDestination":"Custom"}`, string(out))
+}
+
 var mustMatch = utils.MustMatchFn(
 	[]interface{}{ // types with unexported fields
 		sqltypes.Value{},
@@ -111,3 +134,25 @@ func getDescriptionFor(route *Route) PrimitiveDescription {
 		Inputs: []PrimitiveDescription{},
 	}
 }
+
+func TestPlanDescriptionReservedConn(t *testing.T) {
+	lock := &Lock{
+		Keyspace:          &vindexes.Keyspace{Name: "ks"},
+		TargetDestination: key.DestinationKeyspaceID{0},
+		Query:             "select get_lock('lock name', 10) from dual",
+	}
+	route := createRoute()
+	planDescription := PrimitiveToPlanDescription(&Concatenate{Sources: []Primitive{lock, route}})
+
+	// only the Lock node is marked: the flag does not propagate to its parent.
+	assert.False(t, planDescription.ReservedConn)
+	assert.True(t, planDescription.Inputs[0].ReservedConn)
+	assert.False(t, planDescription.Inputs[1].ReservedConn)
+
+	out, err := json.Marshal(planDescription.Inputs[0])
+	require.NoError(t, err)
+	assert.Equal(t, `{"OperatorType":"Lock","Keyspace":{"Name":"ks","Sharded":false},"TargetDestination":"KeyspaceID(00)","ReservedConn":true,"Query":"select get_lock('lock name', 10) from dual"}`, string(out))
+	out, err = json.Marshal(planDescription.Inputs[1])
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "ReservedConn")
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// PlanKey returns a deterministic fingerprint of the primitive tree, suitable for use as a cache key.
+// The key is derived from the plan description, so two trees that route the same queries
+// to the same keyspaces and destinations get the same key, regardless of pointer identity.
+// Bind variable values are never part of a plan, so they do not influence the key either.
+// The descriptions of the primitives always serialize, so PlanKey panics if one does not.
+func PlanKey(p Primitive) string {
+	if p == nil {
+		return ""
+	}
+	b, err := json.Marshal(PrimitiveToPlanDescription(p))
+	if err != nil {
+		panic("BUG: the plan description cannot be serialized: " + err.Error())
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
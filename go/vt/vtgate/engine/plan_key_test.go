@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestPlanKey(t *testing.T) {
+	newLock := func(ks string, dest key.Destination, query string) *Lock {
+		return &Lock{
+			Keyspace:          &vindexes.Keyspace{Name: ks},
+			TargetDestination: dest,
+			Query:             query,
+		}
+	}
+	query := "select get_lock('a', 10) from dual"
+
+	// logically identical trees built separately share the key
+	assert.Equal(t,
+		PlanKey(&Limit{Count: int64PlanValue(1), Input: newLock("ks", key.DestinationKeyspaceID{0}, query)}),
+		PlanKey(&Limit{Count: int64PlanValue(1), Input: newLock("ks", key.DestinationKeyspaceID{0}, query)}),
+	)
+	assert.Equal(t, PlanKey(createRoute()), PlanKey(createRoute()))
+
+	base := PlanKey(newLock("ks", key.DestinationKeyspaceID{0}, query))
+	assert.NotEqual(t, base, PlanKey(newLock("ks", key.DestinationKeyspaceID{0}, "select get_lock('b', 10) from dual")), "query")
+	assert.NotEqual(t, base, PlanKey(newLock("other", key.DestinationKeyspaceID{0}, query)), "keyspace")
+	assert.NotEqual(t, base, PlanKey(newLock("ks", key.DestinationShard("-80"), query)), "destination")
+	assert.Empty(t, PlanKey(nil))
+}
+
+// unmarshalablePrimitive has a description that cannot be serialized.
+type unmarshalablePrimitive struct {
+	fakePrimitive
+}
+
+func (u *unmarshalablePrimitive) description() PrimitiveDescription {
+	return PrimitiveDescription{OperatorType: "Unmarshalable", Other: map[string]interface{}{"ch": make(chan int)}}
+}
+
+func TestPlanKeyPanics(t *testing.T) {
+	assert.Panics(t, func() { PlanKey(&unmarshalablePrimitive{}) })
+}
@@ -18,6 +18,7 @@ package engine
 
 import (
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
@@ -32,9 +33,12 @@ import (
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/schema"
 	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
 )
 
 const (
@@ -62,9 +66,36 @@ type (
 		// if the max memory rows override directive is set to true
 		ExceedsMaxMemoryRows(numRows int) bool
 
+		// MaxAdvisoryLocks returns the maximum number of advisory locks a session can hold, or 0 for no limit.
+		MaxAdvisoryLocks() int
+
+		// MaxLockWait returns the longest time a lock query can wait for its locks, or 0 for no limit.
+		MaxLockWait() time.Duration
+
+		// ReservedConnEnabled returns false if vtgate must not reserve connections on the tablets.
+		ReservedConnEnabled() bool
+
+		// AdvisoryLocksEnabled returns false if the lock queries must be rejected.
+		AdvisoryLocksEnabled() bool
+
 		// SetContextTimeout updates the context and sets a timeout.
 		SetContextTimeout(timeout time.Duration) context.CancelFunc
 
+		// ReservedConnID returns the id of the reserved connection holding the advisory locks
+		// of the session, or 0 if there is none. It ties the locks to a backend connection in diagnostics.
+		ReservedConnID() int64
+
+		// DryRun returns true if the primitives must only route and validate their queries,
+		// without sending the ones with side effects, like lock queries, to vttablet.
+		// It is used by plan verification tooling.
+		DryRun() bool
+
+		// OnLockEvent is called by the primitives once an advisory lock is acquired or released.
+		OnLockEvent(event LockEvent)
+
+		// SetContext makes the cursor use the given context, until the returned function restores the previous one.
+		SetContext(ctx context.Context) func()
+
 		// ErrorGroupCancellableContext updates context that can be cancelled.
 		ErrorGroupCancellableContext() (*errgroup.Group, func())
 
@@ -90,10 +121,27 @@ type (
 
 		Session() SessionActions
 
+		// ExecuteLock sends the lock query on the lock connection of the session.
+		// The query must not be retained once ExecuteLock returns: the Lock primitive reuses it.
 		ExecuteLock(rs *srvtopo.ResolvedShard, query *querypb.BoundQuery) (*sqltypes.Result, error)
 
+		// SetLockWaitTimeout sets @@lock_wait_timeout on the lock connection of the session on the shard,
+		// rounded up to a whole number of seconds, so that the lock query waits as the client expects.
+		SetLockWaitTimeout(rs *srvtopo.ResolvedShard, timeout time.Duration) error
+
 		InTransactionAndIsDML() bool
 
+		// InTransaction returns true if the session has an open transaction
+		InTransaction() bool
+
+		// TargetReadOnly returns true if the tablets of the target are known to vtgate and none
+		// of them is serving, as is the case of a primary being demoted during a failover.
+		TargetReadOnly(target *querypb.Target) bool
+
+		// SessionDestination returns the keyspace and the destination the session is pinned to
+		// by its target string. The destination is nil if the session is not pinned.
+		SessionDestination() (string, key.Destination)
+
 		LookupRowLockShardSession() vtgatepb.CommitOrder
 
 		FindRoutedTable(tablename sqlparser.TableName) (*vindexes.Table, error)
@@ -134,6 +182,25 @@ type (
 		SetReadAfterWriteGTID(string)
 		SetReadAfterWriteTimeout(float64)
 		SetSessionTrackGTIDs(bool)
+
+		// SetReleaseLocksOnCommit sets whether the advisory locks acquired during a transaction are released on commit
+		SetReleaseLocksOnCommit(bool) error
+
+		// AdvisoryLockCount returns the number of times the session acquired the named advisory lock
+		AdvisoryLockCount(name string) int64
+		// AdvisoryLocksHeld returns the number of distinct advisory locks held by the session
+		AdvisoryLocksHeld() int
+		// AdvisoryLocks returns the advisory locks held by the session, along with their count
+		AdvisoryLocks() map[string]int64
+		// LockSessionTarget returns the target of the connection holding the advisory locks, if any
+		LockSessionTarget() *querypb.Target
+		// SetAdvisoryLockCount records the number of times the session acquired the named advisory lock
+		SetAdvisoryLockCount(name string, count int64)
+		// ResetAdvisoryLocks forgets all the advisory locks held by the session
+		ResetAdvisoryLocks()
+		// SessionKey returns a value identifying the connection holding the advisory locks of the session
+		// across its queries, or nil when the session has no such connection
+		SessionKey() interface{}
 	}
 
 	// Plan represents the execution strategy for a given query.
@@ -146,6 +213,7 @@ type (
 		Original     string                  // Original is the original query.
 		Instructions Primitive               // Instructions contains the instructions needed to fulfil the query.
 		BindVarNeeds *sqlparser.BindVarNeeds // Stores BindVars needed to be provided as part of expression rewriting
+		Warnings     []string                // Warnings recorded on the session each time the plan is executed
 
 		mu           sync.Mutex    // Mutex to protect the fields below
 		ExecCount    uint64        // Count of times this plan was executed
@@ -163,7 +231,7 @@ type (
 	// During execution, the Primitive's pass Result objects up the tree structure, until reaching the root,
 	// and its result is passed to the client.
 	Primitive interface {
-		RouteType() string
+		RouteType() RouteType
 		GetKeyspaceName() string
 		GetTableName() string
 		Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error)
@@ -171,6 +239,15 @@ type (
 		GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error)
 		NeedsTransaction() bool
 
+		// HasSideEffects returns true if executing the Primitive changes state beyond returning
+		// a result, by writing rows or acquiring a lock for instance. A Primitive without side
+		// effects is safe to execute speculatively, during a dry run for instance.
+		HasSideEffects() bool
+
+		// EstimatedMemory returns an estimate, in bytes, of the memory the Primitive and its inputs
+		// hold while executing. The Primitives buffering rows, like MemorySort, count them.
+		EstimatedMemory() int64
+
 		// The inputs to this Primitive
 		Inputs() []Primitive
 
@@ -231,6 +308,50 @@ func Exists(m Match, p Primitive) bool {
 	return Find(m, p) != nil
 }
 
+// Walk traverses recursively down the Primitive tree structure, calling visit for every Primitive.
+// The inputs of a Primitive are skipped when visit returns false.
+func Walk(visit func(Primitive) bool, p Primitive) {
+	if p == nil || !visit(p) {
+		return
+	}
+	for _, input := range p.Inputs() {
+		Walk(visit, input)
+	}
+}
+
+// CheckReservedConn returns an error if the plan has a primitive needing a reserved connection,
+// like Lock or ForUpdate, and the VCursor cannot provide one. The primitive itself would only fail
+// once it is reached, possibly after the primitives before it have executed their queries.
+func CheckReservedConn(vcursor VCursor, p Primitive) error {
+	if vcursor.ReservedConnEnabled() {
+		return nil
+	}
+	name := ""
+	Walk(func(p Primitive) bool {
+		if needsReservedConn(p) {
+			name = p.description().OperatorType
+		}
+		return name == ""
+	}, p)
+	if name != "" {
+		return vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "the %s primitive of the plan requires reserved connections, which are disabled", name)
+	}
+	return nil
+}
+
+// needsReservedConn returns true if the primitive itself, not its inputs, needs a reserved connection.
+func needsReservedConn(p Primitive) bool {
+	switch p := p.(type) {
+	case *Lock, *ForUpdate, *TryLock:
+		return true
+	case *analyzeNode:
+		return needsReservedConn(p.Primitive)
+	case analyzeShardLockNode:
+		return needsReservedConn(p.Primitive)
+	}
+	return false
+}
+
 // Size is defined so that Plan can be given to a cache.LRUCache.
 // VTGate needs to maintain a cache of plans. It uses LRUCache, which
 // in turn requires its objects to define a Size function.
@@ -280,3 +401,116 @@ func (noTxNeeded) NeedsTransaction() bool {
 func (txNeeded) NeedsTransaction() bool {
 	return true
 }
+
+// inputsHaveSideEffects returns true if any input of the primitive has side effects.
+func inputsHaveSideEffects(p Primitive) bool {
+	for _, input := range p.Inputs() {
+		if input != nil && input.HasSideEffects() {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// primitiveMemory is the estimated memory of a primitive that does not buffer rows, in bytes.
+	primitiveMemory int64 = 1 << 10
+	// rowMemory is the estimated memory of a buffered row, in bytes.
+	rowMemory int64 = 1 << 10
+	// bufferedRows is the estimated number of rows buffered by a primitive, when it cannot tell.
+	bufferedRows int64 = 1000
+)
+
+// CheckMemory returns an error if the plan is estimated to use more memory than the budget, in bytes.
+func CheckMemory(p Primitive, budget int64) error {
+	if memory := p.EstimatedMemory(); memory > budget {
+		return vterrors.Errorf(vtrpc.Code_RESOURCE_EXHAUSTED, "the plan is estimated to use %d bytes of memory, more than the budget of %d bytes", memory, budget)
+	}
+	return nil
+}
+
+// inputsMemory returns the estimated memory of the inputs of the primitive.
+func inputsMemory(p Primitive) int64 {
+	var memory int64
+	for _, input := range p.Inputs() {
+		if input != nil {
+			memory += input.EstimatedMemory()
+		}
+	}
+	return memory
+}
+
+// primitiveString renders a primitive sending a query to a destination for logs and errors.
+// Literals in the query are redacted, since they can contain user data.
+func primitiveString(name string, keyspace *vindexes.Keyspace, dest key.Destination, query string) string {
+	ks := ""
+	if keyspace != nil {
+		ks = keyspace.Name
+	}
+	redacted, err := sqlparser.RedactSQLQuery(query)
+	if err != nil {
+		redacted = "<unparsable query>"
+	}
+	return fmt.Sprintf("%s{%s, %v, %s}", name, ks, dest, redacted)
+}
+
+// resolveSingleDestination resolves the destination of a query that must be sent to exactly one shard.
+// The kind of query is used in the error message.
+func resolveSingleDestination(vcursor VCursor, keyspace string, dest key.Destination, kind string) (*srvtopo.ResolvedShard, error) {
+	rss, _, err := vcursor.ResolveDestinations(keyspace, nil, []key.Destination{dest})
+	if err != nil {
+		return nil, err
+	}
+	if len(rss) != 1 {
+		return nil, routingError(vtrpc.Code_FAILED_PRECONDITION, keyspace, dest,
+			fmt.Sprintf("%s query cannot be routed to vttablet: resolves to shards %v", kind, shardNames(rss)))
+	}
+	return rss[0], nil
+}
+
+// shardNames returns the keyspace/shard names of the resolved shards, for error messages.
+func shardNames(rss []*srvtopo.ResolvedShard) []string {
+	names := make([]string, len(rss))
+	for i, rs := range rss {
+		names[i] = topoproto.KeyspaceShardString(rs.Target.Keyspace, rs.Target.Shard)
+	}
+	return names
+}
+
+// routingError returns an error for a query that cannot be routed to its destination.
+// The keyspace and the destination, if any, are appended to the detail, so that all the
+// routing failures carry the same context.
+func routingError(code vtrpc.Code, keyspace string, dest key.Destination, detail string) error {
+	if dest == nil {
+		return vterrors.Errorf(code, "%s (keyspace: %s)", detail, keyspace)
+	}
+	return vterrors.Errorf(code, "%s (keyspace: %s, destination: %v)", detail, keyspace, dest)
+}
+
+// GetFieldsContext is the context-aware variant of Primitive.GetFields: the queries sent by the
+// primitive to probe the fields use the given context, so that the probing, when preparing a
+// statement for instance, can be bounded and canceled. It works with any primitive.
+func GetFieldsContext(ctx context.Context, p Primitive, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, vterrors.Wrap(err, "GetFields")
+	}
+	restore := vcursor.SetContext(ctx)
+	defer restore()
+	qr, err := p.GetFields(vcursor, bindVars)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// the error of the primitive may not tell the context is done.
+		return nil, vterrors.Wrap(ctxErr, "GetFields")
+	}
+	return qr, err
+}
+
+// fieldsFromExecute implements GetFields by executing the primitive with wantfields set,
+// and returning only the fields of the result. Primitives can opt in when their execution
+// has no side effect, or when they are given a copy of themselves in fields-only mode.
+func fieldsFromExecute(p Primitive, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	qr, err := p.Execute(vcursor, bindVars, true)
+	if err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{Fields: qr.Fields}, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestResolveSingleDestination(t *testing.T) {
+	vc := &loggingVCursor{shards: []string{"-80", "80-"}}
+	rs, err := resolveSingleDestination(vc, "ks", key.DestinationKeyspaceID{0}, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "ks", rs.Target.Keyspace)
+	assert.Equal(t, "-20", rs.Target.Shard)
+
+	_, err = resolveSingleDestination(vc, "ks", key.DestinationAllShards{}, "test")
+	require.EqualError(t, err, "test query cannot be routed to vttablet: resolves to shards [ks/-80 ks/80-] (keyspace: ks, destination: DestinationAllShards())")
+	assert.Equal(t, vtrpc.Code_FAILED_PRECONDITION, vterrors.Code(err))
+
+	vc = &loggingVCursor{shards: []string{"-80", "80-"}, shardErr: errors.New("no such keyspace")}
+	_, err = resolveSingleDestination(vc, "ks", key.DestinationKeyspaceID{0}, "test")
+	require.EqualError(t, err, "no such keyspace")
+}
+
+func TestHasSideEffects(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	l, err := NewLock(ks, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	assert.True(t, l.HasSideEffects())
+
+	read := &fakePrimitive{}
+	assert.False(t, read.HasSideEffects())
+	assert.False(t, NewRoute(SelectUnsharded, ks, "select 1 from dual", "select 1 from dual where 1 != 1").HasSideEffects())
+
+	// a primitive has the side effects of its inputs.
+	assert.False(t, (&Join{Left: read, Right: &fakePrimitive{}}).HasSideEffects())
+	assert.True(t, (&Join{Left: read, Right: l}).HasSideEffects())
+	assert.True(t, (&Limit{Input: &ForUpdate{Input: read}}).HasSideEffects())
+}
+
+// bufferingPrimitive holds rows of its own, like a primitive sorting its input.
+type bufferingPrimitive struct {
+	fakePrimitive
+	rows int64
+}
+
+func (b *bufferingPrimitive) EstimatedMemory() int64 {
+	return primitiveMemory + b.rows*rowMemory
+}
+
+func TestEstimatedMemory(t *testing.T) {
+	l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock('lock name', 10) from dual")
+	require.NoError(t, err)
+	assert.Equal(t, primitiveMemory, l.EstimatedMemory())
+
+	// a primitive buffering rows uses memory in proportion to them.
+	small := &bufferingPrimitive{rows: 10}
+	large := &bufferingPrimitive{rows: 1000}
+	assert.Greater(t, small.EstimatedMemory(), l.EstimatedMemory())
+	assert.Equal(t, 100*(small.EstimatedMemory()-primitiveMemory), large.EstimatedMemory()-primitiveMemory)
+
+	// the memory of the inputs adds up.
+	limit := &Limit{Input: large}
+	assert.Equal(t, primitiveMemory+large.EstimatedMemory(), limit.EstimatedMemory())
+	sort := &MemorySort{UpperLimit: sqltypes.PlanValue{Value: sqltypes.NewInt64(10)}, Input: l}
+	assert.Equal(t, primitiveMemory+10*rowMemory+l.EstimatedMemory(), sort.EstimatedMemory())
+
+	require.NoError(t, CheckMemory(limit, limit.EstimatedMemory()))
+	err = CheckMemory(limit, l.EstimatedMemory())
+	require.EqualError(t, err, fmt.Sprintf("the plan is estimated to use %d bytes of memory, more than the budget of %d bytes", limit.EstimatedMemory(), l.EstimatedMemory()))
+	assert.Equal(t, vtrpc.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+}
@@ -15,7 +15,7 @@ type Projection struct {
 	noTxNeeded
 }
 
-func (p *Projection) RouteType() string {
+func (p *Projection) RouteType() RouteType {
 	return p.Input.RouteType()
 }
 
@@ -27,6 +27,16 @@ func (p *Projection) GetTableName() string {
 	return p.Input.GetTableName()
 }
 
+// HasSideEffects implements the Primitive interface
+func (p *Projection) HasSideEffects() bool {
+	return inputsHaveSideEffects(p)
+}
+
+// EstimatedMemory implements the Primitive interface
+func (p *Projection) EstimatedMemory() int64 {
+	return primitiveMemory + inputsMemory(p)
+}
+
 func (p *Projection) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	result, err := p.Input.Execute(vcursor, bindVars, wantfields)
 	if err != nil {
@@ -122,6 +132,13 @@ func (p *Projection) Inputs() []Primitive {
 	return []Primitive{p.Input}
 }
 
+// withInputs implements the analyzable interface
+func (p *Projection) withInputs(inputs []Primitive) Primitive {
+	cp := *p
+	cp.Input = inputs[0]
+	return &cp
+}
+
 func (p *Projection) description() PrimitiveDescription {
 	var exprs []string
 	for _, e := range p.Exprs {
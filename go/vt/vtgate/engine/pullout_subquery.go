@@ -46,9 +46,16 @@ func (ps *PulloutSubquery) Inputs() []Primitive {
 	return []Primitive{ps.Subquery, ps.Underlying}
 }
 
+// withInputs implements the analyzable interface
+func (ps *PulloutSubquery) withInputs(inputs []Primitive) Primitive {
+	cp := *ps
+	cp.Subquery, cp.Underlying = inputs[0], inputs[1]
+	return &cp
+}
+
 // RouteType returns a description of the query routing type used by the primitive
-func (ps *PulloutSubquery) RouteType() string {
-	return ps.Opcode.String()
+func (ps *PulloutSubquery) RouteType() RouteType {
+	return pulloutRouteType[ps.Opcode]
 }
 
 // GetKeyspaceName specifies the Keyspace that this primitive routes to.
@@ -61,6 +68,17 @@ func (ps *PulloutSubquery) GetTableName() string {
 	return ps.Underlying.GetTableName()
 }
 
+// HasSideEffects implements the Primitive interface
+func (ps *PulloutSubquery) HasSideEffects() bool {
+	return inputsHaveSideEffects(ps)
+}
+
+// EstimatedMemory implements the Primitive interface
+// It holds the result of the subquery.
+func (ps *PulloutSubquery) EstimatedMemory() int64 {
+	return primitiveMemory + bufferedRows*rowMemory + inputsMemory(ps)
+}
+
 // Execute satisfies the Primitive interface.
 func (ps *PulloutSubquery) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	combinedVars, err := ps.execSubquery(vcursor, bindVars)
@@ -186,6 +204,13 @@ var pulloutName = map[PulloutOpcode]string{
 	PulloutExists: "PulloutExists",
 }
 
+var pulloutRouteType = map[PulloutOpcode]RouteType{
+	PulloutValue:  RouteTypePulloutValue,
+	PulloutIn:     RouteTypePulloutIn,
+	PulloutNotIn:  RouteTypePulloutNotIn,
+	PulloutExists: RouteTypePulloutExists,
+}
+
 func (code PulloutOpcode) String() string {
 	return pulloutName[code]
 }
@@ -193,6 +193,19 @@ var routeName = map[RouteOpcode]string{
 	SelectNone:        "SelectNone",
 }
 
+var routeRouteType = map[RouteOpcode]RouteType{
+	SelectUnsharded:   RouteTypeSelectUnsharded,
+	SelectEqualUnique: RouteTypeSelectEqualUnique,
+	SelectEqual:       RouteTypeSelectEqual,
+	SelectIN:          RouteTypeSelectIN,
+	SelectMultiEqual:  RouteTypeSelectMultiEqual,
+	SelectScatter:     RouteTypeSelectScatter,
+	SelectNext:        RouteTypeSelectNext,
+	SelectDBA:         RouteTypeSelectDBA,
+	SelectReference:   RouteTypeSelectReference,
+	SelectNone:        RouteTypeSelectNone,
+}
+
 var (
 	partialSuccessScatterQueries = stats.NewCounter("PartialSuccessScatterQueries", "Count of partially successful scatter queries")
 )
@@ -204,8 +217,8 @@ func (code RouteOpcode) MarshalJSON() ([]byte, error) {
 }
 
 // RouteType returns a description of the query routing type used by the primitive
-func (route *Route) RouteType() string {
-	return routeName[route.Opcode]
+func (route *Route) RouteType() RouteType {
+	return routeRouteType[route.Opcode]
 }
 
 // GetKeyspaceName specifies the Keyspace that this primitive routes to.
@@ -218,6 +231,16 @@ func (route *Route) GetTableName() string {
 	return route.TableName
 }
 
+// HasSideEffects implements the Primitive interface
+func (route *Route) HasSideEffects() bool {
+	return false
+}
+
+// EstimatedMemory implements the Primitive interface
+func (route *Route) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // SetTruncateColumnCount sets the truncate column count.
 func (route *Route) SetTruncateColumnCount(count int) {
 	route.TruncateColumnCount = count
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import "encoding/json"
+
+// RouteType describes the query routing type used by a primitive.
+// Its string form is used as a label of the query count metrics.
+type RouteType int
+
+// This is the list of RouteType values.
+const (
+	// NoRouteType is used by primitives that do not route queries.
+	NoRouteType = RouteType(iota)
+	RouteTypeAlterVSchema
+	RouteTypeConcatenate
+	RouteTypeConditionalLock
+	RouteTypeDDL
+	RouteTypeDeleteByDestination
+	RouteTypeDeleteEqual
+	RouteTypeDeleteIn
+	RouteTypeDeleteScatter
+	RouteTypeDeleteUnsharded
+	RouteTypeInsertSharded
+	RouteTypeInsertShardedIgnore
+	RouteTypeInsertUnsharded
+	RouteTypeJoin
+	RouteTypeLock
+	RouteTypeLockStatus
+	RouteTypeMergeSort
+	RouteTypeOnlineDDL
+	RouteTypePulloutExists
+	RouteTypePulloutIn
+	RouteTypePulloutNotIn
+	RouteTypePulloutValue
+	RouteTypeRows
+	RouteTypeSQLCalcFoundRows
+	RouteTypeSelectDBA
+	RouteTypeSelectEqual
+	RouteTypeSelectEqualUnique
+	RouteTypeSelectIN
+	RouteTypeSelectMultiEqual
+	RouteTypeSelectNext
+	RouteTypeSelectNone
+	RouteTypeSelectReference
+	RouteTypeSelectScatter
+	RouteTypeSelectUnsharded
+	RouteTypeSend
+	RouteTypeSendDML
+	RouteTypeSet
+	RouteTypeShowLocks
+	RouteTypeTryLock
+	RouteTypeUpdateByDestination
+	RouteTypeUpdateEqual
+	RouteTypeUpdateIn
+	RouteTypeUpdateScatter
+	RouteTypeUpdateTarget
+	RouteTypeUpdateUnsharded
+	RouteTypeVindexMap
+	RouteTypeWaitForGTID
+)
+
+var routeTypeName = map[RouteType]string{
+	NoRouteType:                  "",
+	RouteTypeAlterVSchema:        "AlterVSchema",
+	RouteTypeConcatenate:         "Concatenate",
+	RouteTypeConditionalLock:     "ConditionalLock",
+	RouteTypeDDL:                 "DDL",
+	RouteTypeDeleteByDestination: "DeleteByDestination",
+	RouteTypeDeleteEqual:         "DeleteEqual",
+	RouteTypeDeleteIn:            "DeleteIn",
+	RouteTypeDeleteScatter:       "DeleteScatter",
+	RouteTypeDeleteUnsharded:     "DeleteUnsharded",
+	RouteTypeInsertSharded:       "InsertSharded",
+	RouteTypeInsertShardedIgnore: "InsertShardedIgnore",
+	RouteTypeInsertUnsharded:     "InsertUnsharded",
+	RouteTypeJoin:                "Join",
+	RouteTypeLock:                "lock",
+	RouteTypeLockStatus:          "LockStatus",
+	RouteTypeMergeSort:           "MergeSort",
+	RouteTypeOnlineDDL:           "OnlineDDL",
+	RouteTypePulloutExists:       "PulloutExists",
+	RouteTypePulloutIn:           "PulloutIn",
+	RouteTypePulloutNotIn:        "PulloutNotIn",
+	RouteTypePulloutValue:        "PulloutValue",
+	RouteTypeRows:                "Rows",
+	RouteTypeSQLCalcFoundRows:    "SQLCalcFoundRows",
+	RouteTypeSelectDBA:           "SelectDBA",
+	RouteTypeSelectEqual:         "SelectEqual",
+	RouteTypeSelectEqualUnique:   "SelectEqualUnique",
+	RouteTypeSelectIN:            "SelectIN",
+	RouteTypeSelectMultiEqual:    "SelectMultiEqual",
+	RouteTypeSelectNext:          "SelectNext",
+	RouteTypeSelectNone:          "SelectNone",
+	RouteTypeSelectReference:     "SelectReference",
+	RouteTypeSelectScatter:       "SelectScatter",
+	RouteTypeSelectUnsharded:     "SelectUnsharded",
+	RouteTypeSend:                "Send",
+	RouteTypeSendDML:             "SendDML",
+	RouteTypeSet:                 "Set",
+	RouteTypeShowLocks:           "ShowLocks",
+	RouteTypeTryLock:             "TryLock",
+	RouteTypeUpdateByDestination: "UpdateByDestination",
+	RouteTypeUpdateEqual:         "UpdateEqual",
+	RouteTypeUpdateIn:            "UpdateIn",
+	RouteTypeUpdateScatter:       "UpdateScatter",
+	RouteTypeUpdateTarget:        "UpdateTarget",
+	RouteTypeUpdateUnsharded:     "UpdateUnsharded",
+	RouteTypeVindexMap:           "VindexMap",
+	RouteTypeWaitForGTID:         "WaitForGTID",
+}
+
+// String returns the name of the route type.
+func (rt RouteType) String() string {
+	return routeTypeName[rt]
+}
+
+// MarshalJSON serializes the RouteType as a JSON string.
+// It's used for testing and diagnostics.
+func (rt RouteType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rt.String())
+}
+
+// RouteTypeString returns the route type of the primitive as a string.
+// It is kept for callers that predate the RouteType enum.
+func RouteTypeString(p Primitive) string {
+	return p.RouteType().String()
+}
@@ -38,8 +38,8 @@ func NewRowsPrimitive(rows [][]sqltypes.Value, fields []*querypb.Field) *Rows {
 }
 
 //RouteType implements the Primitive interface
-func (r *Rows) RouteType() string {
-	return "Rows"
+func (r *Rows) RouteType() RouteType {
+	return RouteTypeRows
 }
 
 //GetKeyspaceName implements the Primitive interface
@@ -52,6 +52,17 @@ func (r *Rows) GetTableName() string {
 	return ""
 }
 
+//HasSideEffects implements the Primitive interface
+func (r *Rows) HasSideEffects() bool {
+	return false
+}
+
+//EstimatedMemory implements the Primitive interface
+// It holds its rows.
+func (r *Rows) EstimatedMemory() int64 {
+	return primitiveMemory + int64(len(r.rows))*rowMemory
+}
+
 //Execute implements the Primitive interface
 func (r *Rows) Execute(VCursor, map[string]*querypb.BindVariable, bool) (*sqltypes.Result, error) {
 	return &sqltypes.Result{
@@ -17,9 +17,12 @@ limitations under the License.
 package engine
 
 import (
+	"sort"
+
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
@@ -45,6 +48,11 @@ type Send struct {
 	// SingleShardOnly specifies that the query must be send to only single shard
 	SingleShardOnly bool
 
+	// OrderedShards makes Execute return, and StreamExecute stream, the rows of one shard after the other,
+	// in shard name order. Otherwise, the rows of the shards are interleaved in the order they are received.
+	// It is ignored by the DML queries, which are sent to all the shards at once.
+	OrderedShards bool
+
 	noInputs
 }
 
@@ -54,12 +62,12 @@ func (s *Send) NeedsTransaction() bool {
 }
 
 // RouteType implements Primitive interface
-func (s *Send) RouteType() string {
+func (s *Send) RouteType() RouteType {
 	if s.IsDML {
-		return "SendDML"
+		return RouteTypeSendDML
 	}
 
-	return "Send"
+	return RouteTypeSend
 }
 
 // GetKeyspaceName implements Primitive interface
@@ -72,6 +80,17 @@ func (s *Send) GetTableName() string {
 	return ""
 }
 
+// HasSideEffects implements Primitive interface.
+// The query is sent as is, so it is not known to be a read.
+func (s *Send) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory implements Primitive interface.
+func (s *Send) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // Execute implements Primitive interface
 func (s *Send) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	rss, _, err := vcursor.ResolveDestinations(s.Keyspace.Name, nil, []key.Destination{s.TargetDestination})
@@ -101,6 +120,9 @@ func (s *Send) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariabl
 	}
 
 	rollbackOnError := s.IsDML // for non-dml queries, there's no need to do a rollback
+	if s.OrderedShards && !s.IsDML && len(rss) > 1 {
+		return s.executeOrdered(vcursor, rss, queries[0])
+	}
 	result, errs := vcursor.ExecuteMultiShard(rss, queries, rollbackOnError, canAutocommit)
 	err = vterrors.Aggregate(errs)
 	if err != nil {
@@ -132,6 +154,10 @@ func (s *Send) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindV
 		}
 	}
 
+	if s.OrderedShards && !s.IsDML && len(rss) > 1 {
+		return s.streamExecuteOrdered(vcursor, rss, bindVars, callback)
+	}
+
 	multiBindVars := make([]map[string]*querypb.BindVariable, len(rss))
 	for i := range multiBindVars {
 		multiBindVars[i] = bindVars
@@ -139,6 +165,57 @@ func (s *Send) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindV
 	return vcursor.StreamExecuteMulti(s.Query, rss, multiBindVars, callback)
 }
 
+// streamExecuteOrdered streams the query to one shard at a time, in shard name order,
+// so that the rows of a shard are never interleaved with the rows of another.
+// The fields are only sent once, with the first result.
+func (s *Send) streamExecuteOrdered(vcursor VCursor, rss []*srvtopo.ResolvedShard, bindVars map[string]*querypb.BindVariable, callback func(*sqltypes.Result) error) error {
+	fieldsSent := false
+	for _, rs := range orderShards(rss) {
+		err := vcursor.StreamExecuteMulti(s.Query, []*srvtopo.ResolvedShard{rs}, []map[string]*querypb.BindVariable{bindVars}, func(qr *sqltypes.Result) error {
+			if len(qr.Fields) != 0 {
+				if fieldsSent {
+					if len(qr.Rows) == 0 {
+						return nil
+					}
+					stripped := *qr
+					stripped.Fields = nil
+					qr = &stripped
+				}
+				fieldsSent = true
+			}
+			return callback(qr)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeOrdered sends the query to one shard after the other, in shard name order,
+// and merges their results. The query does not modify rows, so there is nothing to roll back or autocommit.
+func (s *Send) executeOrdered(vcursor VCursor, rss []*srvtopo.ResolvedShard, query *querypb.BoundQuery) (*sqltypes.Result, error) {
+	results := make([]*sqltypes.Result, 0, len(rss))
+	for _, rs := range orderShards(rss) {
+		qr, errs := vcursor.ExecuteMultiShard([]*srvtopo.ResolvedShard{rs}, []*querypb.BoundQuery{query}, false /* rollbackOnError */, false /* canAutocommit */)
+		if err := vterrors.Aggregate(errs); err != nil {
+			return nil, err
+		}
+		results = append(results, qr)
+	}
+	return sqltypes.MergeResults(results)
+}
+
+// orderShards returns a copy of the shards sorted by shard name.
+func orderShards(rss []*srvtopo.ResolvedShard) []*srvtopo.ResolvedShard {
+	ordered := make([]*srvtopo.ResolvedShard, len(rss))
+	copy(ordered, rss)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Target.Shard < ordered[j].Target.Shard
+	})
+	return ordered
+}
+
 // GetFields implements Primitive interface
 func (s *Send) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
 	qr, err := s.Execute(vcursor, bindVars, false)
@@ -149,6 +226,16 @@ func (s *Send) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVaria
 	return qr, nil
 }
 
+// String returns a printable version of the primitive, with the literals of the query redacted.
+func (s *Send) String() string {
+	return primitiveString("Send", s.Keyspace, s.TargetDestination, s.Query)
+}
+
+// Cost implements the Coster interface
+func (s *Send) Cost() int {
+	return destinationCost(s.TargetDestination)
+}
+
 func (s *Send) description() PrimitiveDescription {
 	other := map[string]interface{}{
 		"Query":           s.Query,
@@ -156,6 +243,9 @@ func (s *Send) description() PrimitiveDescription {
 		"IsDML":           s.IsDML,
 		"SingleShardOnly": s.SingleShardOnly,
 	}
+	if s.OrderedShards {
+		other["OrderedShards"] = true
+	}
 	return PrimitiveDescription{
 		OperatorType:      "Send",
 		Keyspace:          s.Keyspace,
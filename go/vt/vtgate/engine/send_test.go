@@ -22,6 +22,7 @@ import (
 
 	"vitess.io/vitess/go/sqltypes"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/vt/key"
@@ -297,3 +298,96 @@ func TestSendGetFields(t *testing.T) {
 	require.Nil(t, qr.Rows)
 	require.Equal(t, 4, len(qr.Fields))
 }
+
+func TestSendExecuteOrderedShards(t *testing.T) {
+	send := &Send{
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		Query:             "dummy_query",
+		TargetDestination: key.DestinationAllShards{},
+		OrderedShards:     true,
+	}
+	fields := sqltypes.MakeTestFields("id|shard", "int64|varchar")
+	vc := &loggingVCursor{
+		// the shards are not resolved in order.
+		shards: []string{"20-", "-20"},
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1|-20", "2|-20"),
+			sqltypes.MakeTestResult(fields, "3|20-", "4|20-"),
+		},
+	}
+	qr, err := send.Execute(vc, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.-20: dummy_query {} false false`,
+		`ExecuteMultiShard ks.20-: dummy_query {} false false`,
+	})
+	expectResult(t, "send.Execute", qr, sqltypes.MakeTestResult(fields, "1|-20", "2|-20", "3|20-", "4|20-"))
+
+	// the shards must return the same fields.
+	vc = &loggingVCursor{
+		shards: []string{"-20", "20-"},
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1|-20"),
+			sqltypes.MakeTestResult(sqltypes.MakeTestFields("id", "int64"), "2"),
+		},
+	}
+	_, err = send.Execute(vc, map[string]*querypb.BindVariable{}, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot merge results with different fields")
+
+	// a DML query is still sent to all the shards at once.
+	send.IsDML = true
+	vc = &loggingVCursor{shards: []string{"20-", "-20"}}
+	_, err = send.Execute(vc, map[string]*querypb.BindVariable{}, true)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.20-: dummy_query {} ks.-20: dummy_query {} true false`,
+	})
+}
+
+func TestSendStreamExecuteOrderedShards(t *testing.T) {
+	send := &Send{
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: true,
+		},
+		Query:             "dummy_query",
+		TargetDestination: key.DestinationAllShards{},
+		OrderedShards:     true,
+	}
+	fields := sqltypes.MakeTestFields("id|shard", "int64|varchar")
+	vc := &loggingVCursor{
+		// the shards are not resolved in order.
+		shards: []string{"20-", "-20"},
+		results: []*sqltypes.Result{
+			sqltypes.MakeTestResult(fields, "1|-20", "2|-20"),
+			sqltypes.MakeTestResult(fields, "3|20-", "4|20-"),
+		},
+	}
+	var results []*sqltypes.Result
+	err := send.StreamExecute(vc, map[string]*querypb.BindVariable{}, true, func(qr *sqltypes.Result) error {
+		results = append(results, qr)
+		return nil
+	})
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`StreamExecuteMulti dummy_query ks.-20: {} `,
+		`StreamExecuteMulti dummy_query ks.20-: {} `,
+	})
+
+	// the rows are grouped by shard, and the fields are only sent once.
+	require.Len(t, results, 2)
+	assert.Equal(t, fields, results[0].Fields)
+	assert.Nil(t, results[1].Fields)
+	var rows [][]sqltypes.Value
+	for _, qr := range results {
+		rows = append(rows, qr.Rows...)
+	}
+	assert.Equal(t, sqltypes.MakeTestResult(fields, "1|-20", "2|-20", "3|20-", "4|20-").Rows, rows)
+}
@@ -95,8 +95,8 @@ type (
 var _ Primitive = (*Set)(nil)
 
 //RouteType implements the Primitive interface method.
-func (s *Set) RouteType() string {
-	return "Set"
+func (s *Set) RouteType() RouteType {
+	return RouteTypeSet
 }
 
 //GetKeyspaceName implements the Primitive interface method.
@@ -109,6 +109,17 @@ func (s *Set) GetTableName() string {
 	return ""
 }
 
+//HasSideEffects implements the Primitive interface method.
+// It changes the session.
+func (s *Set) HasSideEffects() bool {
+	return true
+}
+
+//EstimatedMemory implements the Primitive interface method.
+func (s *Set) EstimatedMemory() int64 {
+	return primitiveMemory + inputsMemory(s)
+}
+
 //Execute implements the Primitive interface method.
 func (s *Set) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
 	input, err := s.Input.Execute(vcursor, bindVars, false)
@@ -150,6 +161,13 @@ func (s *Set) Inputs() []Primitive {
 	return []Primitive{s.Input}
 }
 
+// withInputs implements the analyzable interface
+func (s *Set) withInputs(inputs []Primitive) Primitive {
+	cp := *s
+	cp.Input = inputs[0]
+	return &cp
+}
+
 func (s *Set) description() PrimitiveDescription {
 	other := map[string]interface{}{
 		"Ops": s.Ops,
@@ -368,6 +386,8 @@ func (svss *SysVarSetAware) Execute(vcursor VCursor, env evalengine.ExpressionEn
 		err = svss.setBoolSysVar(env, vcursor.Session().SetClientFoundRows)
 	case sysvars.SkipQueryPlanCache.Name:
 		err = svss.setBoolSysVar(env, vcursor.Session().SetSkipQueryPlanCache)
+	case sysvars.ReleaseLocksOnCommit.Name:
+		err = svss.setBoolSysVar(env, vcursor.Session().SetReleaseLocksOnCommit)
 	case sysvars.TxReadOnly.Name,
 		sysvars.TransactionReadOnly.Name:
 		// TODO (4127): This is a dangerous NOP.
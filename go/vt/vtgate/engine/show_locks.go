@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"sort"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+var _ Primitive = (*ShowLocks)(nil)
+
+// showLocksFields are the fields of the ShowLocks result.
+var showLocksFields = []*querypb.Field{
+	{Name: "Lock_name", Type: sqltypes.VarChar},
+	{Name: "Acquired_at", Type: sqltypes.Datetime},
+	{Name: "Keyspace", Type: sqltypes.VarChar},
+	{Name: "Shard", Type: sqltypes.VarChar},
+}
+
+// ShowLocks primitive returns the advisory locks held by the current session,
+// one row per lock, sorted by name. The acquisition time, in UTC, is NULL when
+// the lock was not acquired through this vtgate.
+type ShowLocks struct {
+	noInputs
+	noTxNeeded
+}
+
+// RouteType is part of the Primitive interface
+func (s *ShowLocks) RouteType() RouteType {
+	return RouteTypeShowLocks
+}
+
+// GetKeyspaceName is part of the Primitive interface
+func (s *ShowLocks) GetKeyspaceName() string {
+	return ""
+}
+
+// GetTableName is part of the Primitive interface
+func (s *ShowLocks) GetTableName() string {
+	return ""
+}
+
+// HasSideEffects is part of the Primitive interface
+func (s *ShowLocks) HasSideEffects() bool {
+	return false
+}
+
+// EstimatedMemory is part of the Primitive interface
+func (s *ShowLocks) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
+// Execute is part of the Primitive interface
+func (s *ShowLocks) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	session := vcursor.Session()
+	locks := session.AdvisoryLocks()
+	names := make([]string, 0, len(locks))
+	for name := range locks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	keyspace, shard := sqltypes.NULL, sqltypes.NULL
+	target := session.LockSessionTarget()
+	if target != nil {
+		keyspace, shard = sqltypes.NewVarChar(target.Keyspace), sqltypes.NewVarChar(target.Shard)
+	}
+	qr := &sqltypes.Result{Fields: showLocksFields}
+	for _, name := range names {
+		acquiredAt := sqltypes.NULL
+		if t, ok := lockWaits.acquiredAt(session.SessionKey(), target, name); ok {
+			acquiredAt = sqltypes.MakeTrusted(sqltypes.Datetime, []byte(t.UTC().Format("2006-01-02 15:04:05")))
+		}
+		qr.Rows = append(qr.Rows, []sqltypes.Value{sqltypes.NewVarChar(name), acquiredAt, keyspace, shard})
+	}
+	qr.RowsAffected = uint64(len(qr.Rows))
+	return qr, nil
+}
+
+// StreamExecute is part of the Primitive interface
+func (s *ShowLocks) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	qr, err := s.Execute(vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// GetFields is part of the Primitive interface
+func (s *ShowLocks) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: showLocksFields}, nil
+}
+
+func (s *ShowLocks) description() PrimitiveDescription {
+	return PrimitiveDescription{OperatorType: "ShowLocks"}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestShowLocks(t *testing.T) {
+	defer func(f func() time.Time) { lockWaits.now = f }(lockWaits.now)
+	lockWaits.now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	getLock := func(name string) *Lock {
+		l, err := NewLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, "select get_lock(:name, 10) from dual",
+			WithLockFuncs(LockFunc{Type: GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar(name)}}))
+		require.NoError(t, err)
+		return l
+	}
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(:name, 10)", "int64"), "1")
+	vc := &loggingVCursor{results: []*sqltypes.Result{acquired, acquired}}
+	defer lockWaits.releasedAll(vc)
+
+	show := &ShowLocks{}
+	fields := sqltypes.MakeTestFields("Lock_name|Acquired_at|Keyspace|Shard", "varchar|datetime|varchar|varchar")
+	qr, err := show.Execute(vc, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: fields}, qr)
+
+	_, err = getLock("show lock b").Execute(vc, nil, false)
+	require.NoError(t, err)
+	_, err = getLock("show lock a").Execute(vc, nil, false)
+	require.NoError(t, err)
+
+	qr, err = show.Execute(vc, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.MakeTestResult(fields,
+		"show lock a|2020-01-02 03:04:05|ks|-20",
+		"show lock b|2020-01-02 03:04:05|ks|-20",
+	), qr)
+
+	qr, err = show.GetFields(vc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: fields}, qr)
+}
@@ -30,8 +30,8 @@ type SingleRow struct {
 }
 
 // RouteType returns a description of the query routing type used by the primitive
-func (s *SingleRow) RouteType() string {
-	return ""
+func (s *SingleRow) RouteType() RouteType {
+	return NoRouteType
 }
 
 // GetKeyspaceName specifies the Keyspace that this primitive routes to.
@@ -44,6 +44,16 @@ func (s *SingleRow) GetTableName() string {
 	return ""
 }
 
+// HasSideEffects implements the Primitive interface
+func (s *SingleRow) HasSideEffects() bool {
+	return false
+}
+
+// EstimatedMemory implements the Primitive interface
+func (s *SingleRow) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // Execute performs a non-streaming exec.
 func (s *SingleRow) Execute(vcursor VCursor, bindVars map[string]*query.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	result := sqltypes.Result{
@@ -33,8 +33,8 @@ type SQLCalcFoundRows struct {
 }
 
 //RouteType implements the Primitive interface
-func (s SQLCalcFoundRows) RouteType() string {
-	return "SQLCalcFoundRows"
+func (s SQLCalcFoundRows) RouteType() RouteType {
+	return RouteTypeSQLCalcFoundRows
 }
 
 //GetKeyspaceName implements the Primitive interface
@@ -47,6 +47,17 @@ func (s SQLCalcFoundRows) GetTableName() string {
 	return s.LimitPrimitive.GetTableName()
 }
 
+//HasSideEffects implements the Primitive interface
+func (s SQLCalcFoundRows) HasSideEffects() bool {
+	return inputsHaveSideEffects(s)
+}
+
+//EstimatedMemory implements the Primitive interface
+// It holds the rows of its limit query.
+func (s SQLCalcFoundRows) EstimatedMemory() int64 {
+	return primitiveMemory + bufferedRows*rowMemory + inputsMemory(s)
+}
+
 //Execute implements the Primitive interface
 func (s SQLCalcFoundRows) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	limitQr, err := s.LimitPrimitive.Execute(vcursor, bindVars, wantfields)
@@ -117,6 +128,12 @@ func (s SQLCalcFoundRows) Inputs() []Primitive {
 	return []Primitive{s.LimitPrimitive, s.CountPrimitive}
 }
 
+// withInputs implements the analyzable interface
+func (s SQLCalcFoundRows) withInputs(inputs []Primitive) Primitive {
+	s.LimitPrimitive, s.CountPrimitive = inputs[0], inputs[1]
+	return s
+}
+
 func (s SQLCalcFoundRows) description() PrimitiveDescription {
 	return PrimitiveDescription{
 		OperatorType: "SQL_CALC_FOUND_ROWS",
@@ -36,7 +36,7 @@ func (sq *Subquery) NeedsTransaction() bool {
 }
 
 // RouteType returns a description of the query routing type used by the primitive
-func (sq *Subquery) RouteType() string {
+func (sq *Subquery) RouteType() RouteType {
 	return sq.Subquery.RouteType()
 }
 
@@ -50,6 +50,16 @@ func (sq *Subquery) GetTableName() string {
 	return sq.Subquery.GetTableName()
 }
 
+// HasSideEffects implements the Primitive interface
+func (sq *Subquery) HasSideEffects() bool {
+	return inputsHaveSideEffects(sq)
+}
+
+// EstimatedMemory implements the Primitive interface
+func (sq *Subquery) EstimatedMemory() int64 {
+	return primitiveMemory + inputsMemory(sq)
+}
+
 // Execute performs a non-streaming exec.
 func (sq *Subquery) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	inner, err := sq.Subquery.Execute(vcursor, bindVars, wantfields)
@@ -80,6 +90,13 @@ func (sq *Subquery) Inputs() []Primitive {
 	return []Primitive{sq.Subquery}
 }
 
+// withInputs implements the analyzable interface
+func (sq *Subquery) withInputs(inputs []Primitive) Primitive {
+	cp := *sq
+	cp.Subquery = inputs[0]
+	return &cp
+}
+
 // buildResult builds a new result by pulling the necessary columns from
 // the subquery in the requested order.
 func (sq *Subquery) buildResult(inner *sqltypes.Result) *sqltypes.Result {
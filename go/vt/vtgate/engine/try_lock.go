@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var _ Primitive = (*TryLock)(nil)
+
+const (
+	// tryLockNameVar is the bind variable holding the lock name in the TryLock query.
+	tryLockNameVar = "__trylock_name"
+	// tryLockColumn is the name of the column returned by TryLock.
+	tryLockColumn = "try_lock"
+)
+
+// TryLock primitive acquires an advisory lock without waiting for it.
+// It returns 1 if the lock was acquired and 0 if the lock is held by another session.
+// Use NewTryLock to create one.
+type TryLock struct {
+	// Keyspace specifies the keyspace to send the query to.
+	Keyspace *vindexes.Keyspace
+
+	// TargetDestination specifies an explicit target destination to send the query to.
+	TargetDestination key.Destination
+
+	// Name is the name of the lock.
+	Name sqltypes.PlanValue
+
+	noInputs
+
+	noTxNeeded
+}
+
+// NewTryLock creates a TryLock primitive acquiring the named lock on the given destination of the keyspace.
+func NewTryLock(keyspace *vindexes.Keyspace, dest key.Destination, name sqltypes.PlanValue) (*TryLock, error) {
+	if keyspace == nil {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "try lock primitive requires a keyspace")
+	}
+	if dest == nil {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "try lock primitive requires a target destination")
+	}
+	if name.IsNull() {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "try lock primitive requires a lock name")
+	}
+	return &TryLock{
+		Keyspace:          keyspace,
+		TargetDestination: dest,
+		Name:              name,
+	}, nil
+}
+
+// lock returns the Lock primitive sending GET_LOCK with a zero timeout, so that
+// MySQL answers right away instead of waiting for the lock.
+func (t *TryLock) lock() *Lock {
+	return &Lock{
+		Keyspace:          t.Keyspace,
+		TargetDestination: t.TargetDestination,
+		Query:             "select get_lock(:" + tryLockNameVar + ", 0) from dual",
+		LockFuncs: []LockFunc{{
+			Type:       GetLock,
+			Name:       sqltypes.PlanValue{Key: tryLockNameVar},
+			ColumnName: tryLockColumn,
+		}},
+	}
+}
+
+// RouteType is part of the Primitive interface
+func (t *TryLock) RouteType() RouteType {
+	return RouteTypeTryLock
+}
+
+// GetKeyspaceName is part of the Primitive interface
+func (t *TryLock) GetKeyspaceName() string {
+	return t.Keyspace.Name
+}
+
+// GetTableName is part of the Primitive interface
+func (t *TryLock) GetTableName() string {
+	return "dual"
+}
+
+// HasSideEffects is part of the Primitive interface.
+// It acquires the lock when it is free.
+func (t *TryLock) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory is part of the Primitive interface.
+func (t *TryLock) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
+// Execute is part of the Primitive interface
+func (t *TryLock) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	name, err := t.Name.ResolveValue(bindVars)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "invalid lock name")
+	}
+	bv := make(map[string]*querypb.BindVariable, len(bindVars)+1)
+	for k, v := range bindVars {
+		bv[k] = v
+	}
+	bv[tryLockNameVar] = sqltypes.ValueBindVariable(name)
+
+	// the lock graph of vtgate only knows the locks of its own sessions: the query is always
+	// sent, so that MySQL decides whether the lock is free.
+	return t.lock().Execute(vcursor, bv, wantfields)
+}
+
+func (t *TryLock) fields() []*querypb.Field {
+	return []*querypb.Field{{Name: tryLockColumn, Type: sqltypes.Int64}}
+}
+
+// StreamExecute is part of the Primitive interface
+func (t *TryLock) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	qr, err := t.Execute(vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// GetFields is part of the Primitive interface
+func (t *TryLock) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: t.fields()}, nil
+}
+
+// String returns a printable version of the primitive.
+func (t *TryLock) String() string {
+	return primitiveString("TryLock", t.Keyspace, t.TargetDestination, t.lock().Query)
+}
+
+// Cost implements the Coster interface.
+func (t *TryLock) Cost() int {
+	return singleShardCost
+}
+
+func (t *TryLock) description() PrimitiveDescription {
+	return PrimitiveDescription{
+		OperatorType:      "TryLock",
+		Keyspace:          t.Keyspace,
+		TargetDestination: t.TargetDestination,
+		Other: map[string]interface{}{
+			"Name": t.Name,
+		},
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestNewTryLock(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	name := sqltypes.PlanValue{Key: "name"}
+
+	_, err := NewTryLock(nil, key.DestinationKeyspaceID{0}, name)
+	require.EqualError(t, err, "try lock primitive requires a keyspace")
+
+	_, err = NewTryLock(ks, nil, name)
+	require.EqualError(t, err, "try lock primitive requires a target destination")
+
+	_, err = NewTryLock(ks, key.DestinationKeyspaceID{0}, sqltypes.PlanValue{})
+	require.EqualError(t, err, "try lock primitive requires a lock name")
+}
+
+func TestTryLock(t *testing.T) {
+	tl, err := NewTryLock(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, sqltypes.PlanValue{Key: "name"})
+	require.NoError(t, err)
+	bv := map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable("try lock")}
+
+	// the lock is free: it is acquired by the first session.
+	acquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(:__trylock_name, 0)", "varchar"), "1")
+	session1 := &loggingVCursor{results: []*sqltypes.Result{acquired}}
+	qr, err := tl.Execute(session1, bv, true)
+	require.NoError(t, err)
+	defer lockWaits.releasedAll(session1)
+	assert.Equal(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields("try_lock", "int64"), "1"), qr)
+	session1.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteLock ks.-20: select get_lock(:__trylock_name, 0) from dual {__trylock_name: type:VARBINARY value:"try lock" name: type:VARBINARY value:"try lock" }`,
+		"SetAdvisoryLockCount try lock 1",
+	})
+
+	// the lock is held by the first session: the query of the second one is still sent,
+	// MySQL answers 0 right away.
+	notAcquired := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock(:__trylock_name, 0)", "varchar"), "0")
+	session2 := &loggingVCursor{results: []*sqltypes.Result{notAcquired}}
+	qr, err = tl.Execute(session2, bv, true)
+	require.NoError(t, err)
+	want := sqltypes.MakeTestResult(sqltypes.MakeTestFields("try_lock", "int64"), "0")
+	assert.Equal(t, want, qr)
+	assert.Equal(t, querypb.Type_INT64, qr.Fields[0].Type)
+	session2.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteLock ks.-20: select get_lock(:__trylock_name, 0) from dual {__trylock_name: type:VARBINARY value:"try lock" name: type:VARBINARY value:"try lock" }`,
+	})
+	assert.Empty(t, session2.advisoryLocks)
+
+	fields, err := tl.GetFields(session2, bv)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: want.Fields}, fields)
+
+	// the checks of the lock query apply even when the lock is held by another session.
+	defer func() { testAdvisoryLocksDisabled = false }()
+	testAdvisoryLocksDisabled = true
+	session2.Rewind()
+	_, err = tl.Execute(session2, bv, true)
+	require.EqualError(t, err, "advisory locks are disabled")
+	session2.ExpectLog(t, nil)
+}
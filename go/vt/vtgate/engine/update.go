@@ -62,9 +62,17 @@ var updName = map[DMLOpcode]string{
 	ByDestination: "UpdateByDestination",
 }
 
+var updRouteType = map[DMLOpcode]RouteType{
+	Unsharded:     RouteTypeUpdateUnsharded,
+	Equal:         RouteTypeUpdateEqual,
+	In:            RouteTypeUpdateIn,
+	Scatter:       RouteTypeUpdateScatter,
+	ByDestination: RouteTypeUpdateByDestination,
+}
+
 // RouteType returns a description of the query routing type used by the primitive
-func (upd *Update) RouteType() string {
-	return updName[upd.Opcode]
+func (upd *Update) RouteType() RouteType {
+	return updRouteType[upd.Opcode]
 }
 
 // GetKeyspaceName specifies the Keyspace that this primitive routes to.
@@ -80,6 +88,16 @@ func (upd *Update) GetTableName() string {
 	return ""
 }
 
+// HasSideEffects implements the Primitive interface
+func (upd *Update) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory implements the Primitive interface
+func (upd *Update) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // Execute performs a non-streaming exec.
 func (upd *Update) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	if upd.QueryTimeout != 0 {
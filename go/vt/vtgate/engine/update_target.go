@@ -44,8 +44,8 @@ func (updTarget *UpdateTarget) description() PrimitiveDescription {
 }
 
 // RouteType implements the Primitive interface
-func (updTarget *UpdateTarget) RouteType() string {
-	return "UpdateTarget"
+func (updTarget *UpdateTarget) RouteType() RouteType {
+	return RouteTypeUpdateTarget
 }
 
 // GetKeyspaceName implements the Primitive interface
@@ -58,6 +58,17 @@ func (updTarget *UpdateTarget) GetTableName() string {
 	return ""
 }
 
+// HasSideEffects implements the Primitive interface.
+// It changes the target of the session.
+func (updTarget *UpdateTarget) HasSideEffects() bool {
+	return true
+}
+
+// EstimatedMemory implements the Primitive interface.
+func (updTarget *UpdateTarget) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // Execute implements the Primitive interface
 func (updTarget *UpdateTarget) Execute(vcursor VCursor, bindVars map[string]*query.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	err := vcursor.Session().SetTarget(updTarget.Target)
@@ -64,6 +64,10 @@ var vindexOpcodeName = map[VindexOpcode]string{
 	VindexMap: "VindexMap",
 }
 
+var vindexRouteType = map[VindexOpcode]RouteType{
+	VindexMap: RouteTypeVindexMap,
+}
+
 // MarshalJSON serializes the VindexOpcode into a JSON representation.
 // It's used for testing and diagnostics.
 func (code VindexOpcode) MarshalJSON() ([]byte, error) {
@@ -71,8 +75,8 @@ func (code VindexOpcode) MarshalJSON() ([]byte, error) {
 }
 
 // RouteType returns a description of the query routing type used by the primitive
-func (vf *VindexFunc) RouteType() string {
-	return vindexOpcodeName[vf.Opcode]
+func (vf *VindexFunc) RouteType() RouteType {
+	return vindexRouteType[vf.Opcode]
 }
 
 // GetKeyspaceName specifies the Keyspace that this primitive routes to.
@@ -85,6 +89,16 @@ func (vf *VindexFunc) GetTableName() string {
 	return ""
 }
 
+// HasSideEffects implements the Primitive interface
+func (vf *VindexFunc) HasSideEffects() bool {
+	return false
+}
+
+// EstimatedMemory implements the Primitive interface
+func (vf *VindexFunc) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 // Execute performs a non-streaming exec.
 func (vf *VindexFunc) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	return vf.mapVindex(vcursor, bindVars)
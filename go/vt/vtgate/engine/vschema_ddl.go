@@ -49,8 +49,8 @@ func (v *AlterVSchema) description() PrimitiveDescription {
 }
 
 //RouteType implements the Primitive interface
-func (v *AlterVSchema) RouteType() string {
-	return "AlterVSchema"
+func (v *AlterVSchema) RouteType() RouteType {
+	return RouteTypeAlterVSchema
 }
 
 //GetKeyspaceName implements the Primitive interface
@@ -63,6 +63,16 @@ func (v *AlterVSchema) GetTableName() string {
 	return v.AlterVschemaDDL.Table.Name.String()
 }
 
+//HasSideEffects implements the Primitive interface
+func (v *AlterVSchema) HasSideEffects() bool {
+	return true
+}
+
+//EstimatedMemory implements the Primitive interface
+func (v *AlterVSchema) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
 //Execute implements the Primitive interface
 func (v *AlterVSchema) Execute(vcursor VCursor, bindVars map[string]*query.BindVariable, wantfields bool) (*sqltypes.Result, error) {
 	err := vcursor.ExecuteVSchema(v.Keyspace.Name, v.AlterVschemaDDL)
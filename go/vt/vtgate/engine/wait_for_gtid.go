@@ -0,0 +1,224 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var _ Primitive = (*WaitForGTID)(nil)
+
+const (
+	// waitForGTIDSetVar is the bind variable holding the GTID set in the WaitForGTID query.
+	waitForGTIDSetVar = "__gtid_set"
+	// waitForGTIDColumn is the name of the column returned by WaitForGTID.
+	waitForGTIDColumn = "wait_for_executed_gtid_set"
+	// waitForGTIDGrace is added to the timeout of the vtgate call, so that MySQL
+	// reports the timeout of the wait instead of vtgate canceling the query.
+	waitForGTIDGrace = time.Second
+)
+
+// WaitForGTID primitive waits for a tablet to have executed a GTID set, using
+// WAIT_FOR_EXECUTED_GTID_SET. It returns 0 once the GTID set is executed, and
+// 1 if the timeout is reached first. It is used for read-your-writes patterns.
+// Use NewWaitForGTID to create one.
+type WaitForGTID struct {
+	// Keyspace specifies the keyspace to send the query to.
+	Keyspace *vindexes.Keyspace
+
+	// TargetDestination specifies an explicit target destination to send the query to.
+	TargetDestination key.Destination
+
+	// GTIDSet is the GTID set to wait for.
+	GTIDSet sqltypes.PlanValue
+
+	// Timeout is the optional timeout of the wait. Without a timeout, the wait never ends
+	// until the GTID set is executed.
+	Timeout time.Duration
+
+	noInputs
+
+	noTxNeeded
+}
+
+// NewWaitForGTID creates a WaitForGTID primitive that sends the query to the given destination of the keyspace.
+func NewWaitForGTID(keyspace *vindexes.Keyspace, dest key.Destination, gtidSet sqltypes.PlanValue, timeout time.Duration) (*WaitForGTID, error) {
+	if keyspace == nil {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "wait for gtid primitive requires a keyspace")
+	}
+	if dest == nil {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "wait for gtid primitive requires a target destination")
+	}
+	if gtidSet.IsNull() {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "wait for gtid primitive requires a gtid set")
+	}
+	if timeout < 0 {
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "invalid wait for gtid timeout: %v", timeout)
+	}
+	return &WaitForGTID{
+		Keyspace:          keyspace,
+		TargetDestination: dest,
+		GTIDSet:           gtidSet,
+		Timeout:           timeout,
+	}, nil
+}
+
+// query returns the query sent to the tablet. MySQL expects the timeout in seconds.
+func (w *WaitForGTID) query() string {
+	if w.Timeout == 0 {
+		return fmt.Sprintf("select wait_for_executed_gtid_set(:%s) from dual", waitForGTIDSetVar)
+	}
+	return fmt.Sprintf("select wait_for_executed_gtid_set(:%s, %s) from dual", waitForGTIDSetVar, strconv.FormatFloat(w.Timeout.Seconds(), 'f', -1, 64))
+}
+
+// RouteType is part of the Primitive interface
+func (w *WaitForGTID) RouteType() RouteType {
+	return RouteTypeWaitForGTID
+}
+
+// GetKeyspaceName is part of the Primitive interface
+func (w *WaitForGTID) GetKeyspaceName() string {
+	return w.Keyspace.Name
+}
+
+// GetTableName is part of the Primitive interface
+func (w *WaitForGTID) GetTableName() string {
+	return "dual"
+}
+
+// HasSideEffects is part of the Primitive interface
+func (w *WaitForGTID) HasSideEffects() bool {
+	return false
+}
+
+// EstimatedMemory is part of the Primitive interface
+func (w *WaitForGTID) EstimatedMemory() int64 {
+	return primitiveMemory
+}
+
+// Execute is part of the Primitive interface
+func (w *WaitForGTID) Execute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	gtidSet, err := w.GTIDSet.ResolveValue(bindVars)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "invalid gtid set")
+	}
+	if gtidSet.IsNull() {
+		return nil, vterrors.New(vtrpc.Code_INVALID_ARGUMENT, "invalid gtid set: NULL")
+	}
+
+	if w.Timeout != 0 {
+		// the deadline only applies to the wait, the later queries of the plan do not inherit it.
+		ctx, cancel := context.WithTimeout(vcursor.Context(), w.Timeout+waitForGTIDGrace)
+		defer cancel()
+		restore := vcursor.SetContext(ctx)
+		defer restore()
+	}
+
+	rs, err := resolveSingleDestination(vcursor, w.Keyspace.Name, w.TargetDestination, "wait for gtid")
+	if err != nil {
+		return nil, err
+	}
+	if vcursor.DryRun() {
+		return &sqltypes.Result{Fields: w.fields()}, nil
+	}
+	query := &querypb.BoundQuery{
+		Sql:           w.query(),
+		BindVariables: map[string]*querypb.BindVariable{waitForGTIDSetVar: sqltypes.ValueBindVariable(gtidSet)},
+	}
+	qr, errs := vcursor.ExecuteMultiShard([]*srvtopo.ResolvedShard{rs}, []*querypb.BoundQuery{query}, false, false)
+	if err := vterrors.Aggregate(errs); err != nil {
+		return nil, err
+	}
+	return w.typeResult(qr)
+}
+
+// typeResult returns the result with the column type and name MySQL would return.
+func (w *WaitForGTID) typeResult(qr *sqltypes.Result) (*sqltypes.Result, error) {
+	typed := &sqltypes.Result{
+		Fields:       w.fields(),
+		RowsAffected: qr.RowsAffected,
+	}
+	for _, row := range qr.Rows {
+		if len(row) != 1 {
+			return nil, vterrors.Errorf(vtrpc.Code_INTERNAL, "unexpected wait for gtid result: %v", row)
+		}
+		val := row[0]
+		if !val.IsNull() {
+			var err error
+			if val, err = sqltypes.NewValue(sqltypes.Int64, val.ToBytes()); err != nil {
+				return nil, err
+			}
+		}
+		typed.Rows = append(typed.Rows, []sqltypes.Value{val})
+	}
+	return typed, nil
+}
+
+func (w *WaitForGTID) fields() []*querypb.Field {
+	return []*querypb.Field{{Name: waitForGTIDColumn, Type: sqltypes.Int64}}
+}
+
+// StreamExecute is part of the Primitive interface
+func (w *WaitForGTID) StreamExecute(vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	qr, err := w.Execute(vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// GetFields is part of the Primitive interface
+func (w *WaitForGTID) GetFields(vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: w.fields()}, nil
+}
+
+// String returns a printable version of the primitive.
+func (w *WaitForGTID) String() string {
+	return primitiveString("WaitForGTID", w.Keyspace, w.TargetDestination, w.query())
+}
+
+// Cost implements the Coster interface.
+func (w *WaitForGTID) Cost() int {
+	return singleShardCost
+}
+
+func (w *WaitForGTID) description() PrimitiveDescription {
+	other := map[string]interface{}{
+		"GTIDSet": w.GTIDSet,
+	}
+	if w.Timeout != 0 {
+		other["Timeout"] = w.Timeout.String()
+	}
+	return PrimitiveDescription{
+		OperatorType:      "WaitForGTID",
+		Keyspace:          w.Keyspace,
+		TargetDestination: w.TargetDestination,
+		Other:             other,
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestNewWaitForGTID(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	gtid := sqltypes.PlanValue{Key: "gtid"}
+
+	_, err := NewWaitForGTID(nil, key.DestinationShard("-20"), gtid, 0)
+	require.EqualError(t, err, "wait for gtid primitive requires a keyspace")
+
+	_, err = NewWaitForGTID(ks, nil, gtid, 0)
+	require.EqualError(t, err, "wait for gtid primitive requires a target destination")
+
+	_, err = NewWaitForGTID(ks, key.DestinationShard("-20"), sqltypes.PlanValue{}, 0)
+	require.EqualError(t, err, "wait for gtid primitive requires a gtid set")
+
+	_, err = NewWaitForGTID(ks, key.DestinationShard("-20"), gtid, -time.Second)
+	require.EqualError(t, err, "invalid wait for gtid timeout: -1s")
+}
+
+func TestWaitForGTID(t *testing.T) {
+	w, err := NewWaitForGTID(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, sqltypes.PlanValue{Key: "gtid"}, 1500*time.Millisecond)
+	require.NoError(t, err)
+	bv := map[string]*querypb.BindVariable{"gtid": sqltypes.StringBindVariable("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5")}
+
+	tabletResult := sqltypes.MakeTestResult(sqltypes.MakeTestFields("wait_for_executed_gtid_set(:__gtid_set, 1.5)", "varchar"), "0")
+	vc := &loggingVCursor{results: []*sqltypes.Result{tabletResult}}
+	qr, err := w.Execute(vc, bv, true)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields("wait_for_executed_gtid_set", "int64"), "0"), qr)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteMultiShard ks.-20: select wait_for_executed_gtid_set(:__gtid_set, 1.5) from dual {__gtid_set: type:VARBINARY value:"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5" } false false`,
+	})
+
+	// the timeout of the wait is reached.
+	w.Timeout = 0
+	vc = &loggingVCursor{results: []*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("wait", "int64"), "1")}}
+	qr, err = w.Execute(vc, bv, true)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.MakeTestResult(sqltypes.MakeTestFields("wait_for_executed_gtid_set", "int64"), "1"), qr)
+	vc.ExpectLog(t, []string{
+		"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)",
+		`ExecuteMultiShard ks.-20: select wait_for_executed_gtid_set(:__gtid_set) from dual {__gtid_set: type:VARBINARY value:"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5" } false false`,
+	})
+
+	// in dry run mode, the wait is routed but not sent.
+	vc = &loggingVCursor{dryRun: true}
+	qr, err = w.Execute(vc, bv, true)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{Fields: sqltypes.MakeTestFields("wait_for_executed_gtid_set", "int64")}, qr)
+	vc.ExpectLog(t, []string{"ResolveDestinations ks [] Destinations:DestinationKeyspaceID(00)"})
+
+	// the gtid set must be routed to a single shard.
+	vc = &loggingVCursor{shards: []string{"-20", "20-"}}
+	w.TargetDestination = key.DestinationAllShards{}
+	_, err = w.Execute(vc, bv, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wait for gtid query cannot be routed to vttablet")
+}
+
+// gtidDeadlineVCursor records the deadline of the context of the wait.
+type gtidDeadlineVCursor struct {
+	*loggingVCursor
+	deadline time.Time
+	ok       bool
+}
+
+func (vc *gtidDeadlineVCursor) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries []*querypb.BoundQuery, rollbackOnError, canAutocommit bool) (*sqltypes.Result, []error) {
+	vc.deadline, vc.ok = vc.Context().Deadline()
+	return vc.loggingVCursor.ExecuteMultiShard(rss, queries, rollbackOnError, canAutocommit)
+}
+
+func TestWaitForGTIDDeadline(t *testing.T) {
+	w, err := NewWaitForGTID(&vindexes.Keyspace{Name: "ks"}, key.DestinationKeyspaceID{0}, sqltypes.PlanValue{Key: "gtid"}, time.Minute)
+	require.NoError(t, err)
+	bv := map[string]*querypb.BindVariable{"gtid": sqltypes.StringBindVariable("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5")}
+
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("wait", "int64"), "0")
+	vc := &gtidDeadlineVCursor{loggingVCursor: &loggingVCursor{results: []*sqltypes.Result{result}}}
+	before := time.Now()
+	_, err = w.Execute(vc, bv, true)
+	require.NoError(t, err)
+	// the wait is given some grace over the timeout of the query.
+	require.True(t, vc.ok)
+	assert.False(t, vc.deadline.Before(before.Add(time.Minute)), "deadline %v is before %v", vc.deadline, before.Add(time.Minute))
+
+	// the later queries of the plan do not inherit the deadline of the wait.
+	_, ok := vc.Context().Deadline()
+	assert.False(t, ok)
+}
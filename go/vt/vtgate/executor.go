@@ -38,6 +38,7 @@ import (
 
 	"vitess.io/vitess/go/acl"
 	"vitess.io/vitess/go/cache"
+	"vitess.io/vitess/go/json2"
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/stats"
@@ -99,7 +100,9 @@ type Executor struct {
 	normalize    bool
 	streamSize   int
 	plans        *cache.LRUCache
+	fields       *engine.FieldsCache
 	vschemaStats *VSchemaStats
+	hooks        *hooks
 
 	vm *VSchemaManager
 }
@@ -120,6 +123,8 @@ func NewExecutor(ctx context.Context, serv srvtopo.Server, cell string, resolver
 		scatterConn: resolver.scatterConn,
 		txConn:      resolver.scatterConn.txConn,
 		plans:       cache.NewLRUCache(queryPlanCacheSize),
+		fields:      engine.NewFieldsCache(queryPlanCacheSize),
+		hooks:       &hooks{},
 		normalize:   normalize,
 		streamSize:  streamSize,
 	}
@@ -313,6 +318,8 @@ func (e *Executor) addNeededBindVars(bindVarNeeds *sqlparser.BindVarNeeds, bindV
 				}
 			})
 			bindVars[key] = sqltypes.StringBindVariable(v)
+		case sysvars.ReleaseLocksOnCommit.Name:
+			bindVars[key] = sqltypes.BoolBindVariable(session.ReleaseLocksOnCommit)
 		}
 	}
 
@@ -426,9 +433,24 @@ func (e *Executor) handleSavepoint(ctx context.Context, safeSession *SafeSession
 // CloseSession releases the current connection, which rollbacks open transactions and closes reserved connections.
 // It is called then the MySQL servers closes the connection to its client.
 func (e *Executor) CloseSession(ctx context.Context, safeSession *SafeSession) error {
+	if safeSession.AdvisoryLocksHeld() != 0 {
+		// the locks may be held on any reserved connection of the session.
+		if _, err := e.txConn.ReleaseAllEverywhere(ctx, safeSession); err != nil {
+			log.Warningf("failed to release the advisory locks of the session: %v", err)
+		}
+	}
 	return e.txConn.ReleaseAll(ctx, safeSession)
 }
 
+// ReleaseLocks releases the advisory locks of the session and its lock connection.
+// Any open transaction of the session is left untouched.
+func (e *Executor) ReleaseLocks(ctx context.Context, safeSession *SafeSession) error {
+	if _, err := e.txConn.ReleaseAllEverywhere(ctx, safeSession); err != nil {
+		return err
+	}
+	return e.txConn.ReleaseLock(ctx, safeSession)
+}
+
 func (e *Executor) handleSet(ctx context.Context, sql string, logStats *LogStats) (*sqltypes.Result, error) {
 	stmt, err := sqlparser.Parse(sql)
 	if err != nil {
@@ -1123,6 +1145,12 @@ func (e *Executor) StreamExecute(ctx context.Context, method string, safeSession
 		return err
 	}
 
+	if err := checkPlan(vcursor, plan); err != nil {
+		logStats.Error = err
+		return err
+	}
+	recordPlanWarnings(safeSession, plan)
+
 	execStart := time.Now()
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
 
@@ -1182,7 +1210,7 @@ func (e *Executor) StreamExecute(ctx context.Context, method string, safeSession
 	}
 
 	logStats.ExecuteTime = time.Since(execStart)
-	e.updateQueryCounts(plan.Instructions.RouteType(), plan.Instructions.GetKeyspaceName(), plan.Instructions.GetTableName(), int64(logStats.ShardQueries))
+	e.updateQueryCounts(plan.Instructions.RouteType().String(), plan.Instructions.GetKeyspaceName(), plan.Instructions.GetTableName(), int64(logStats.ShardQueries))
 
 	return err
 }
@@ -1245,6 +1273,7 @@ func (e *Executor) SaveVSchema(vschema *vindexes.VSchema, stats *VSchemaStats) {
 	e.vschema = vschema
 	e.vschemaStats = stats
 	e.plans.Clear()
+	e.fields.Clear()
 
 	if vschemaCounters != nil {
 		vschemaCounters.Add("Reload", 1)
@@ -1342,6 +1371,10 @@ func (e *Executor) ServeHTTP(response http.ResponseWriter, request *http.Request
 
 	switch request.URL.Path {
 	case pathQueryPlans:
+		if request.URL.Query().Get("format") == "proto" {
+			e.writePlanProtos(response)
+			return
+		}
 		returnAsJSON(response, e.plans.Items())
 	case pathVSchema:
 		returnAsJSON(response, e.VSchema())
@@ -1364,6 +1397,38 @@ func returnAsJSON(response http.ResponseWriter, stuff interface{}) {
 	_, _ = response.Write(ebuf.Bytes())
 }
 
+// planProto is a plan of the query cache with its instructions in the JSON mapping of their
+// protobuf form, which, unlike the plain JSON form, keeps the types of the primitive fields.
+type planProto struct {
+	Key          string
+	Original     string
+	Instructions json.RawMessage
+}
+
+// writePlanProtos shows the current plans in the query cache in protobuf form, for the tooling
+// consuming the plans.
+func (e *Executor) writePlanProtos(response http.ResponseWriter) {
+	var plans []planProto
+	for _, item := range e.plans.Items() {
+		plan, ok := item.Value.(*engine.Plan)
+		if !ok || plan.Instructions == nil {
+			continue
+		}
+		pd, err := engine.DescriptionToProto(engine.PrimitiveToPlanDescription(plan.Instructions))
+		if err != nil {
+			_, _ = response.Write([]byte(err.Error()))
+			return
+		}
+		instructions, err := json2.MarshalPB(pd)
+		if err != nil {
+			_, _ = response.Write([]byte(err.Error()))
+			return
+		}
+		plans = append(plans, planProto{Key: item.Key, Original: plan.Original, Instructions: instructions})
+	}
+	returnAsJSON(response, plans)
+}
+
 // Plans returns the LRU plan cache
 func (e *Executor) Plans() *cache.LRUCache {
 	return e.plans
@@ -1603,7 +1668,7 @@ func (e *Executor) handlePrepare(ctx context.Context, safeSession *SafeSession,
 		return nil, err
 	}
 
-	qr, err := plan.Instructions.GetFields(vcursor, bindVars)
+	qr, err := e.fields.GetFields(ctx, plan.Instructions, vcursor, bindVars)
 	logStats.ExecuteTime = time.Since(execStart)
 	var errCount uint64
 	if err != nil {
@@ -2391,6 +2391,11 @@ func TestSelectLock(t *testing.T) {
 			TabletAlias: sbc1.Tablet().Alias,
 			ReservedId:  1,
 		},
+		AdvisoryLock:   map[string]int64{"lock name": 1},
+		TxAdvisoryLock: map[string]int64{"lock name": 1},
+		Warnings: []*querypb.QueryWarning{{
+			Message: "advisory lock 'lock name' acquired in a transaction is not released when the transaction ends",
+		}},
 		FoundRows: 1,
 		RowCount:  -1,
 	}
@@ -2405,11 +2410,55 @@ func TestSelectLock(t *testing.T) {
 		Sql:           "select release_lock('lock name') from dual",
 		BindVariables: map[string]*querypb.BindVariable{},
 	})
+	wantSession.AdvisoryLock = nil
+	wantSession.TxAdvisoryLock = nil
+	// the warnings of a query do not outlive it.
+	wantSession.Warnings = nil
 	exec(executor, session, "select release_lock('lock name') from dual")
 	utils.MustMatch(t, wantQueries, sbc1.Queries, "")
 	utils.MustMatch(t, wantSession, session.Session, "")
 }
 
+func TestSelectLockPrepare(t *testing.T) {
+	executor, sbc1, _, _ := createExecutorEnv()
+	session := NewSafeSession(nil)
+
+	// the placeholders of a prepared statement are sent as NULL.
+	bv := map[string]*querypb.BindVariable{"v1": sqltypes.NullBindVariable}
+	fields, err := executor.Prepare(context.Background(), "TestExecute", session, "select get_lock(:v1, 10) from dual", bv)
+	require.NoError(t, err)
+	require.NotEmpty(t, fields)
+	assert.Equal(t, "get_lock(:v1, 10)", fields[0].Name)
+	assert.Equal(t, querypb.Type_INT64, fields[0].Type)
+
+	wantQueries := []*querypb.BoundQuery{{
+		Sql:           "select get_lock(:v1, 10) from dual where 1 != 1",
+		BindVariables: bv,
+	}}
+	utils.MustMatch(t, wantQueries, sbc1.Queries, "")
+	// describing the statement does not reserve the lock connection.
+	assert.False(t, session.InLockSession())
+	assert.EqualValues(t, 0, sbc1.ReserveCount.Get())
+}
+
+func TestSelectLockOrderWarning(t *testing.T) {
+	executor, sbc1, _, _ := createExecutorEnv()
+	session := NewSafeSession(nil)
+
+	result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("get_lock('b', 10)|get_lock('a', 10)", "int64|int64"), "1|1")
+	sbc1.SetResults([]*sqltypes.Result{result, result})
+	wantWarnings := []*querypb.QueryWarning{{
+		Message: "advisory locks are not acquired in sorted order, which can lead to deadlocks: b, a",
+	}}
+
+	// the warning is recorded each time the statement executes, including from the plan cache.
+	for i := 0; i < 2; i++ {
+		_, err := exec(executor, session, "select get_lock('b', 10), get_lock('a', 10) from dual")
+		require.NoError(t, err)
+		utils.MustMatch(t, wantWarnings, session.Warnings, "")
+	}
+}
+
 func TestSelectFromInformationSchema(t *testing.T) {
 	executor, sbc1, _, _ := createExecutorEnv()
 	session := NewSafeSession(nil)
@@ -2432,3 +2481,24 @@ func TestSelectFromInformationSchema(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, sbc1.StringQueries(), []string{"select * from INFORMATION_SCHEMA.`TABLES` where TABLE_SCHEMA = :__vtschemaname"})
 }
+
+func TestSelectMaxPlanMemory(t *testing.T) {
+	defer func(max int64) { *maxPlanMemory = max }(*maxPlanMemory)
+	*maxPlanMemory = 100 * 1024
+	executor, _, _, _ := createExecutorEnv()
+
+	// a single route fits in the budget.
+	_, err := executorExec(executor, "select id from user where id = 1", nil)
+	require.NoError(t, err)
+
+	// a join holds the rows of its left side.
+	_, err = exec(executor, NewSafeSession(&vtgatepb.Session{TargetString: "@master"}), "select u1.id, u2.id from user u1 join user u2 where u1.id = 1 and u2.id = 3")
+	require.EqualError(t, err, "the plan is estimated to use 1027072 bytes of memory, more than the budget of 102400 bytes")
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+
+	// streaming does not bypass the budget.
+	err = executor.StreamExecute(context.Background(), "TestSelectMaxPlanMemory", NewSafeSession(&vtgatepb.Session{TargetString: "@master"}),
+		"select u1.id, u2.id from user u1 join user u2 where u1.id = 1 and u2.id = 3", nil, querypb.Target{TabletType: topodatapb.TabletType_MASTER},
+		func(*sqltypes.Result) error { return nil })
+	require.EqualError(t, err, "the plan is estimated to use 1027072 bytes of memory, more than the budget of 102400 bytes")
+}
@@ -1806,6 +1806,33 @@ func TestDebugVSchema(t *testing.T) {
 	}
 }
 
+func TestDebugQueryPlansProto(t *testing.T) {
+	executor, _, _, _ := createExecutorEnv()
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "@master"})
+	_, err := executor.Execute(context.Background(), "TestDebugQueryPlansProto", session, "select id from main1", nil)
+	require.NoError(t, err)
+
+	resp := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/debug/query_plans?format=proto", nil)
+	executor.ServeHTTP(resp, req)
+	var plans []struct {
+		Original     string
+		Instructions map[string]interface{}
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &plans), resp.Body.String())
+	var instructions map[string]interface{}
+	for _, plan := range plans {
+		if plan.Original == "select id from main1" {
+			instructions = plan.Instructions
+		}
+	}
+	require.NotNil(t, instructions, resp.Body.String())
+	assert.Equal(t, "Route", instructions["operatorType"])
+	// the primitive fields keep their type.
+	other := instructions["other"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"stringValue": "select id from main1"}, other["Query"])
+}
+
 func TestGenerateCharsetRows(t *testing.T) {
 	rows := make([][]sqltypes.Value, 0, 4)
 	rows0 := [][]sqltypes.Value{
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+// ResolveHook is called every time a primitive resolves its destinations to shards,
+// with the keyspace and destinations it asked for and the shards they resolved to.
+type ResolveHook func(ctx context.Context, keyspace string, destinations []key.Destination, rss []*srvtopo.ResolvedShard)
+
+// hooks holds the hooks registered on an Executor by embedding applications, to observe
+// the queries it executes. Hooks are called synchronously, so they must not block.
+// A nil hooks has no hook.
+type hooks struct {
+	mu        sync.Mutex
+	lockEvent []engine.LockEventHook
+	resolve   []ResolveHook
+}
+
+// RegisterLockEventHook registers a hook called every time a session acquires or releases an advisory lock.
+func (e *Executor) RegisterLockEventHook(hook engine.LockEventHook) {
+	e.hooks.mu.Lock()
+	defer e.hooks.mu.Unlock()
+	e.hooks.lockEvent = append(e.hooks.lockEvent, hook)
+}
+
+// RegisterResolveHook registers a hook observing how the primitives, like Lock,
+// are routed to the shards, e.g. for auditing.
+func (e *Executor) RegisterResolveHook(hook ResolveHook) {
+	e.hooks.mu.Lock()
+	defer e.hooks.mu.Unlock()
+	e.hooks.resolve = append(e.hooks.resolve, hook)
+}
+
+func (h *hooks) onLockEvent(ctx context.Context, event engine.LockEvent) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	lockEvent := h.lockEvent
+	h.mu.Unlock()
+	for _, hook := range lockEvent {
+		hook(ctx, event)
+	}
+}
+
+func (h *hooks) onResolve(ctx context.Context, keyspace string, destinations []key.Destination, rss []*srvtopo.ResolvedShard) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	resolve := h.resolve
+	h.mu.Unlock()
+	for _, hook := range resolve {
+		hook(ctx, keyspace, destinations, rss)
+	}
+}
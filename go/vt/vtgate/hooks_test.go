@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+func TestLockEventHook(t *testing.T) {
+	executor, _, _, _ := createExecutorEnv()
+	var users []string
+	var events []engine.LockEvent
+	executor.RegisterLockEventHook(func(ctx context.Context, event engine.LockEvent) {
+		users = append(users, callerid.GetUsername(callerid.ImmediateCallerIDFromContext(ctx)))
+		events = append(events, event)
+	})
+
+	ctx := callerid.NewContext(context.Background(), nil, callerid.NewImmediateCallerID("user1"))
+	vc, err := newVCursorImpl(ctx, NewSafeSession(nil), makeComments(""), executor, NewLogStats(ctx, "Test", "", nil), executor.vm, executor.VSchema(), executor.resolver.resolver, nil)
+	require.NoError(t, err)
+	vc.OnLockEvent(engine.LockEvent{Type: engine.GetLock, Name: "lock name", Outcome: "1"})
+	vc.OnLockEvent(engine.LockEvent{Type: engine.ReleaseAllLocks, Outcome: "1"})
+
+	assert.Equal(t, []string{"user1", "user1"}, users)
+	assert.Equal(t, []engine.LockEvent{
+		{Type: engine.GetLock, Name: "lock name", Outcome: "1"},
+		{Type: engine.ReleaseAllLocks, Outcome: "1"},
+	}, events)
+
+	// the hooks are registered on an executor: the vcursors of the others do not call them.
+	other, _, _, _ := createExecutorEnv()
+	vc, err = newVCursorImpl(ctx, NewSafeSession(nil), makeComments(""), other, NewLogStats(ctx, "Test", "", nil), other.vm, other.VSchema(), other.resolver.resolver, nil)
+	require.NoError(t, err)
+	vc.OnLockEvent(engine.LockEvent{Type: engine.GetLock, Name: "lock name", Outcome: "1"})
+	assert.Len(t, events, 2)
+}
+
+func TestResolveHook(t *testing.T) {
+	executor, _, _, _ := createExecutorEnv()
+	var resolved []string
+	executor.RegisterResolveHook(func(ctx context.Context, keyspace string, destinations []key.Destination, rss []*srvtopo.ResolvedShard) {
+		for _, rs := range rss {
+			resolved = append(resolved, keyspace+" "+key.DestinationsString(destinations)+" "+rs.Target.Keyspace+"/"+rs.Target.Shard)
+		}
+	})
+
+	session := NewSafeSession(nil)
+	vc, err := newVCursorImpl(ctx, session, makeComments(""), executor, NewLogStats(ctx, "Test", "", nil), executor.vm, executor.VSchema(), executor.resolver.resolver, nil)
+	require.NoError(t, err)
+
+	l, err := engine.NewLock(executor.VSchema().Keyspaces["TestExecutor"].Keyspace, key.DestinationShard("-20"), "select get_lock('lock name', 10) from dual",
+		engine.WithLockFuncs(engine.LockFunc{Type: engine.GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}}))
+	require.NoError(t, err)
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+
+	// the hook fires once per lock query, with the shard the lock is acquired on.
+	assert.Equal(t, []string{"TestExecutor Destinations:DestinationShard(-20) TestExecutor/-20"}, resolved)
+}
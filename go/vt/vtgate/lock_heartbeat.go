@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/log"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// heartbeatIdleLocks sends the lock heartbeat to the lock connections of the sessions not
+// running a command, which the heartbeat sent along with the queries of a session does not
+// reach, so that the tablet does not release them, and their locks, while the client is idle.
+// The busy connections are skipped: the heartbeat must not use the lock connection of a session
+// at the same time as its own queries.
+func (vh *vtgateHandler) heartbeatIdleLocks() {
+	vh.mu.Lock()
+	conns := make(map[*mysql.Conn]*connActivity, len(vh.activity))
+	for c, a := range vh.activity {
+		conns[c] = a
+	}
+	vh.mu.Unlock()
+
+	for c, a := range conns {
+		if !a.tryAcquire() {
+			continue
+		}
+		vh.heartbeatLocks(c)
+		<-a.busy
+	}
+}
+
+// heartbeatLocks sends the lock heartbeat to the lock connection of the session of the
+// connection, if it holds one and the heartbeat is due.
+func (vh *vtgateHandler) heartbeatLocks(c *mysql.Conn) {
+	session, _ := c.ClientData.(*vtgatepb.Session)
+	if session == nil {
+		return
+	}
+	safeSession := NewSafeSession(session)
+	if !safeSession.InLockSession() || !safeSession.TriggerLockHeartBeat() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *lockHeartbeatTime)
+	defer cancel()
+	if err := vh.vtg.executor.scatterConn.lockHeartbeat(ctx, safeSession); err != nil {
+		log.Warningf("Locking heartbeat of connection %v failed, held locks might be released: %v", c.ConnectionID, err)
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/mysql"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
+)
+
+func TestHeartbeatIdleLocks(t *testing.T) {
+	executor, sbc1, sbc2, _ := createExecutorEnv()
+	vh := newVtgateHandler(&VTGate{executor: executor})
+
+	lockSession := func(sbc *sandboxconn.SandboxConn, shard string) *vtgatepb.Session {
+		return &vtgatepb.Session{
+			AdvisoryLock: map[string]int64{"lock": 1},
+			LockSession: &vtgatepb.Session_ShardSession{
+				Target:      &querypb.Target{Keyspace: "TestExecutor", Shard: shard, TabletType: topodatapb.TabletType_MASTER},
+				TabletAlias: sbc.Tablet().Alias,
+				ReservedId:  1,
+			},
+		}
+	}
+	idle := &mysql.Conn{ConnectionID: 1}
+	idle.ClientData = lockSession(sbc1, "-20")
+	vh.startActivity(idle)()
+	// the busy connection is running a command: the heartbeat must not share its lock connection.
+	busy := &mysql.Conn{ConnectionID: 2}
+	busy.ClientData = lockSession(sbc2, "20-40")
+	done := vh.startActivity(busy)
+	defer done()
+
+	vh.heartbeatIdleLocks()
+	assert.EqualValues(t, 1, sbc1.ExecCount.Get())
+	assert.Equal(t, "select 1", sbc1.Queries[0].Sql)
+	assert.NotZero(t, idle.ClientData.(*vtgatepb.Session).LastLockHeartbeat)
+	assert.EqualValues(t, 0, sbc2.ExecCount.Get())
+
+	// the next heartbeat is not due yet.
+	vh.heartbeatIdleLocks()
+	assert.EqualValues(t, 1, sbc1.ExecCount.Get())
+}
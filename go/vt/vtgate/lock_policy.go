@@ -0,0 +1,44 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"flag"
+
+	"vitess.io/vitess/go/flagutil"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// advisoryLocksDeniedKeyspaces are the keyspaces in which the advisory locks are rejected.
+var advisoryLocksDeniedKeyspaces flagutil.StringListValue
+
+func init() {
+	flag.Var(&advisoryLocksDeniedKeyspaces, "advisory_locks_denied_keyspaces", "Comma-separated list of keyspaces in which the queries using advisory lock functions are rejected")
+}
+
+// applyAdvisoryLockPolicy marks the keyspaces of the vschema in which the advisory locks are denied.
+// The Lock primitives find the policy in the keyspace they lock in.
+func applyAdvisoryLockPolicy(vschema *vindexes.VSchema) {
+	if vschema == nil {
+		return
+	}
+	for _, name := range advisoryLocksDeniedKeyspaces {
+		if ks := vschema.Keyspaces[name]; ks != nil && ks.Keyspace != nil {
+			ks.Keyspace.AdvisoryLocksDenied = true
+		}
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestApplyAdvisoryLockPolicy(t *testing.T) {
+	defer func() { advisoryLocksDeniedKeyspaces = nil }()
+	advisoryLocksDeniedKeyspaces = []string{"denied", "unknown"}
+
+	vschema, err := vindexes.BuildVSchema(&vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"allowed": {},
+			"denied": {
+				Tables: map[string]*vschemapb.Table{"t1": {}},
+			},
+		},
+	})
+	require.NoError(t, err)
+	applyAdvisoryLockPolicy(vschema)
+
+	assert.False(t, vschema.Keyspaces["allowed"].Keyspace.AdvisoryLocksDenied)
+	assert.True(t, vschema.Keyspaces["denied"].Keyspace.AdvisoryLocksDenied)
+	// the tables share the keyspace, so the primitives planned on them see the policy.
+	assert.True(t, vschema.Keyspaces["denied"].Tables["t1"].Keyspace.AdvisoryLocksDenied)
+}
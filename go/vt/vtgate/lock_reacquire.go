@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+var (
+	// reacquireLocks re-acquires the advisory locks of a session after its lock connection was lost.
+	reacquireLocks = flag.Bool("reacquire_locks_on_reconnect", false, "If true, the advisory locks held by a session whose lock connection was lost, e.g. after a tablet restart, are acquired again on a new connection by its next lock query, instead of failing it. Another session can acquire the locks in between, in which case the query fails.")
+)
+
+// reacquireLocks reserves a new lock connection on the target of rs, and acquires on it,
+// without waiting, the advisory locks the session held on its lost connection. Each lock
+// is acquired as many times as the session did. If any of the locks cannot be acquired,
+// the new connection is released, along with the locks acquired on it, and an error is returned.
+func (stc *ScatterConn) reacquireLocks(ctx context.Context, rs *srvtopo.ResolvedShard, session *SafeSession) error {
+	locks := session.AdvisoryLocks()
+	names := make([]string, 0, len(locks))
+	for name := range locks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		exprs     []string
+		lockNames []string
+	)
+	bindVars := make(map[string]*querypb.BindVariable)
+	for _, name := range names {
+		for i := int64(0); i < locks[name]; i++ {
+			key := fmt.Sprintf("__lock%d", len(exprs))
+			exprs = append(exprs, "get_lock(:"+key+", 0)")
+			lockNames = append(lockNames, name)
+			bindVars[key] = sqltypes.StringBindVariable(name)
+		}
+	}
+	sql := "select " + strings.Join(exprs, ", ") + " from dual"
+
+	info, err := lockInfo(rs.Target, session)
+	if err != nil {
+		return err
+	}
+	qs, err := getQueryService(rs, info)
+	if err != nil {
+		return err
+	}
+	qr, reservedID, alias, err := qs.ReserveExecute(ctx, rs.Target, session.SetPreQueries(), sql, bindVars, 0 /* transactionID */, session.Session.Options)
+	if reservedID != 0 {
+		session.SetLockSession(&vtgatepb.Session_ShardSession{
+			Target:      rs.Target,
+			ReservedId:  reservedID,
+			TabletAlias: alias,
+		})
+	}
+	if err == nil {
+		err = checkLocksReacquired(qr, lockNames)
+	}
+	if err != nil {
+		if reservedID != 0 {
+			_ = stc.txConn.ReleaseLock(ctx, session)
+		}
+		return err
+	}
+	engine.LocksReacquired(session.LockKey(), rs.Target, names)
+	return nil
+}
+
+// checkLocksReacquired returns an error if the result of the lock query sent by reacquireLocks
+// shows that one of the locks was not acquired.
+func checkLocksReacquired(qr *sqltypes.Result, names []string) error {
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != len(names) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result while reacquiring the advisory locks: %v", qr.Rows)
+	}
+	for i, value := range qr.Rows[0] {
+		if value.ToString() != "1" {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "advisory lock connection lost; lock '%s' could not be reacquired", names[i])
+		}
+	}
+	return nil
+}
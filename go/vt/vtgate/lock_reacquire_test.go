@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/discovery"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/srvtopo"
+)
+
+func TestLockReacquire(t *testing.T) {
+	defer func(reacquire bool) { *reacquireLocks = reacquire }(*reacquireLocks)
+	*reacquireLocks = true
+
+	keyspace := "keyspace"
+	createSandbox(keyspace)
+	hc := discovery.NewFakeHealthCheck()
+	sc := newTestScatterConn(hc, new(sandboxTopo), "aa")
+	sbc0 := hc.AddTestTablet("aa", "0", 1, keyspace, "0", topodatapb.TabletType_MASTER, true, 1, nil)
+	rs := &srvtopo.ResolvedShard{
+		Target:  &querypb.Target{Keyspace: keyspace, Shard: "0", TabletType: topodatapb.TabletType_MASTER},
+		Gateway: sc.gateway,
+	}
+	getLock := &querypb.BoundQuery{Sql: "select get_lock('c', 10) from dual"}
+	ctx := context.Background()
+
+	session := NewSafeSession(&vtgatepb.Session{})
+	_, err := sc.ExecuteLock(ctx, rs, &querypb.BoundQuery{Sql: "select get_lock('a', 10), get_lock('b', 10), get_lock('b', 10) from dual"}, session)
+	require.NoError(t, err)
+	session.SetAdvisoryLockCount("a", 1)
+	session.SetAdvisoryLockCount("b", 2)
+	loseLockConn := func() {
+		t.Helper()
+		sbc0.EphemeralShardErr = mysql.NewSQLError(mysql.CRServerGone, mysql.SSUnknownSQLState, "lost connection")
+		_, err := sc.ExecuteLock(ctx, rs, &querypb.BoundQuery{Sql: "select 1"}, session)
+		require.Error(t, err)
+		require.True(t, session.LockLost())
+		sbc0.Queries = nil
+	}
+	reacquire := &querypb.BoundQuery{
+		Sql: "select get_lock(:__lock0, 0), get_lock(:__lock1, 0), get_lock(:__lock2, 0) from dual",
+		BindVariables: map[string]*querypb.BindVariable{
+			"__lock0": sqltypes.StringBindVariable("a"),
+			"__lock1": sqltypes.StringBindVariable("b"),
+			"__lock2": sqltypes.StringBindVariable("b"),
+		},
+	}
+	reacquireFields := sqltypes.MakeTestFields("a|b|b", "int64|int64|int64")
+
+	// the locks are acquired again on a new connection, before the lock query is sent on it.
+	loseLockConn()
+	sbc0.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(reacquireFields, "1|1|1")})
+	_, err = sc.ExecuteLock(ctx, rs, getLock, session)
+	require.NoError(t, err)
+	assert.True(t, session.InLockSession())
+	assert.Equal(t, map[string]int64{"a": 1, "b": 2}, session.AdvisoryLocks())
+	utils.MustMatch(t, []*querypb.BoundQuery{reacquire, {Sql: getLock.Sql, BindVariables: map[string]*querypb.BindVariable{}}}, sbc0.Queries, "")
+	assert.EqualValues(t, 2, sbc0.ReserveCount.Get())
+
+	// "b" was acquired by another session in between: the lock query fails, and the
+	// new connection is released along with "a".
+	loseLockConn()
+	sbc0.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(reacquireFields, "1|0|0")})
+	_, err = sc.ExecuteLock(ctx, rs, getLock, session)
+	require.EqualError(t, err, "advisory lock connection lost; lock 'b' could not be reacquired")
+	assert.False(t, session.InLockSession())
+	assert.False(t, session.LockLost())
+	assert.Nil(t, session.AdvisoryLocks())
+	utils.MustMatch(t, []*querypb.BoundQuery{reacquire}, sbc0.Queries, "")
+	assert.EqualValues(t, 3, sbc0.ReserveCount.Get())
+	assert.EqualValues(t, 1, sbc0.ReleaseCount.Get())
+}
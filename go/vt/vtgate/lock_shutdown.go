@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"flag"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/log"
+)
+
+var (
+	// lockReleaseShutdownTimeout bounds the release of the advisory locks when vtgate shuts down.
+	lockReleaseShutdownTimeout = flag.Duration("lock_release_shutdown_timeout", 5*time.Second, "Time given to release the advisory locks of the mysql protocol sessions when vtgate shuts down, once the connections are drained.")
+)
+
+// releaseAllLocks releases the advisory locks of all the sessions of the handler, so that
+// the sessions of other vtgates do not wait for locks held through a vtgate going away.
+// It gives up after timeout: the locks of the sessions not released by then go away
+// when their connections are closed.
+func (vh *vtgateHandler) releaseAllLocks(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	vh.mu.Lock()
+	conns := make([]*mysql.Conn, 0, len(vh.connections))
+	for c := range vh.connections {
+		conns = append(conns, c)
+	}
+	vh.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, c := range conns {
+		wg.Add(1)
+		go func(c *mysql.Conn) {
+			defer wg.Done()
+			defer vh.startActivity(c)()
+			vh.releaseLocks(ctx, c, "vtgate is shutting down")
+		}(c)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Warningf("Timed out releasing the advisory locks of the sessions after %v", timeout)
+	}
+}
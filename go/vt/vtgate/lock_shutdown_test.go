@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/mysql"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestReleaseAllLocksAtShutdown(t *testing.T) {
+	executor, sbc1, _, _ := createExecutorEnv()
+	vh := newVtgateHandler(&VTGate{executor: executor})
+
+	var conns []*mysql.Conn
+	for id := int64(1); id <= 2; id++ {
+		c := &mysql.Conn{ConnectionID: uint32(id)}
+		c.ClientData = &vtgatepb.Session{
+			AdvisoryLock: map[string]int64{"lock": 1},
+			LockSession: &vtgatepb.Session_ShardSession{
+				Target:      &querypb.Target{Keyspace: "TestExecutor", Shard: "-20", TabletType: topodatapb.TabletType_MASTER},
+				TabletAlias: sbc1.Tablet().Alias,
+				ReservedId:  id,
+			},
+		}
+		vh.NewConnection(c)
+		conns = append(conns, c)
+	}
+	// a session without locks is left alone.
+	vh.NewConnection(&mysql.Conn{ConnectionID: 3})
+
+	vh.releaseAllLocks(time.Second)
+	assert.EqualValues(t, 2, sbc1.ReleaseCount.Get())
+	assert.Len(t, sbc1.Queries, 2)
+	for _, c := range conns {
+		session := c.ClientData.(*vtgatepb.Session)
+		assert.Empty(t, session.AdvisoryLock)
+		assert.Nil(t, session.LockSession)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/log"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+var (
+	// advisoryLockIdleTimeout is how long a session holding advisory locks may stay idle.
+	advisoryLockIdleTimeout = flag.Duration("advisory_lock_idle_timeout", 0, "If set, the advisory locks of the mysql protocol sessions idle for longer than this are released. Disabled by default.")
+)
+
+// connActivity tracks when a mysql connection last ran a command.
+type connActivity struct {
+	// busy holds a value while the connection runs a command, or while its locks are swept.
+	// Unlike a mutex, it lets the sweeper skip the busy connections rather than wait for them.
+	busy chan struct{}
+	// since is the time the last command of the connection ended.
+	since time.Time
+}
+
+// tryAcquire marks the connection busy, unless it already is.
+func (a *connActivity) tryAcquire() bool {
+	select {
+	case a.busy <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// startActivity marks the connection busy until the returned function is called.
+func (vh *vtgateHandler) startActivity(c *mysql.Conn) func() {
+	vh.mu.Lock()
+	a := vh.activity[c]
+	if a == nil {
+		a = &connActivity{busy: make(chan struct{}, 1)}
+		vh.activity[c] = a
+	}
+	vh.mu.Unlock()
+
+	a.busy <- struct{}{}
+	return func() {
+		a.since = vh.now()
+		<-a.busy
+	}
+}
+
+// sweepIdleLocks releases the advisory locks of the sessions idle for longer than idleTimeout.
+func (vh *vtgateHandler) sweepIdleLocks(idleTimeout time.Duration) {
+	vh.mu.Lock()
+	conns := make(map[*mysql.Conn]*connActivity, len(vh.activity))
+	for c, a := range vh.activity {
+		conns[c] = a
+	}
+	vh.mu.Unlock()
+
+	idleBefore := vh.now().Add(-idleTimeout)
+	reason := fmt.Sprintf("idle for longer than %v", idleTimeout)
+	for c, a := range conns {
+		// a connection running a command is not idle, and the command could be waiting
+		// on a lock held by another connection of the sweep.
+		if !a.tryAcquire() {
+			continue
+		}
+		if !a.since.IsZero() && a.since.Before(idleBefore) {
+			vh.releaseIdleLocks(c, reason)
+		}
+		<-a.busy
+	}
+}
+
+// releaseIdleLocks releases the advisory locks of the idle connection, if any.
+func (vh *vtgateHandler) releaseIdleLocks(c *mysql.Conn, reason string) {
+	ctx := context.Background()
+	if *mysqlQueryTimeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *mysqlQueryTimeout)
+		defer cancel()
+	}
+	vh.releaseLocks(ctx, c, reason)
+}
+
+// releaseLocks releases the advisory locks of the session of the connection, if any.
+// The caller must keep the connection from running a command meanwhile.
+func (vh *vtgateHandler) releaseLocks(ctx context.Context, c *mysql.Conn, reason string) {
+	session, _ := c.ClientData.(*vtgatepb.Session)
+	if session == nil {
+		return
+	}
+	safeSession := NewSafeSession(session)
+	locks := safeSession.AdvisoryLocks()
+	if len(locks) == 0 {
+		return
+	}
+
+	if err := vh.vtg.executor.ReleaseLocks(ctx, safeSession); err != nil {
+		log.Warningf("Failed to release the advisory locks of connection %v, %s: %v", c.ConnectionID, reason, err)
+		return
+	}
+	for name := range locks {
+		log.Infof("Released advisory lock %q of connection %v, %s", name, c.ConnectionID, reason)
+	}
+}
+
+// startLockSweeper starts sweeping the idle sessions of the handler, until stopped.
+// Sessions are checked every half idleTimeout.
+func startLockSweeper(vh *vtgateHandler, idleTimeout time.Duration, after func(time.Duration) <-chan time.Time) *lockTicker {
+	return startLockTicker(idleTimeout/2, after, func() {
+		vh.sweepIdleLocks(idleTimeout)
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+func TestLockSweeper(t *testing.T) {
+	executor, sbc1, _, _ := createExecutorEnv()
+	sbc1.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("release_all_locks()", "int64"), "1")})
+
+	start := time.Unix(1000, 0)
+	now := start
+	vh := newVtgateHandler(&VTGate{executor: executor})
+	vh.now = func() time.Time { return now }
+
+	c := &mysql.Conn{ConnectionID: 1}
+	c.ClientData = &vtgatepb.Session{
+		AdvisoryLock: map[string]int64{"lock": 1},
+		LockSession: &vtgatepb.Session_ShardSession{
+			Target:      &querypb.Target{Keyspace: "TestExecutor", Shard: "-20", TabletType: topodatapb.TabletType_MASTER},
+			TabletAlias: sbc1.Tablet().Alias,
+			ReservedId:  1,
+		},
+	}
+	// the connection ran a command at start.
+	vh.startActivity(c)()
+
+	// the test clock only ticks when the test advances it.
+	ticks := make(chan time.Time)
+	waiting := make(chan time.Duration, 1)
+	s := startLockSweeper(vh, time.Minute, func(d time.Duration) <-chan time.Time {
+		waiting <- d
+		return ticks
+	})
+	defer s.Stop()
+	assert.Equal(t, 30*time.Second, <-waiting)
+	// the sweeper waits for the next tick once the sweep is done.
+	sweep := func() {
+		ticks <- now
+		<-waiting
+	}
+
+	now = start.Add(30 * time.Second)
+	sweep()
+	assert.EqualValues(t, 0, sbc1.ReleaseCount.Get())
+	assert.Len(t, c.ClientData.(*vtgatepb.Session).AdvisoryLock, 1)
+
+	now = start.Add(time.Minute + time.Second)
+	sweep()
+	assert.EqualValues(t, 1, sbc1.ReleaseCount.Get())
+	session := c.ClientData.(*vtgatepb.Session)
+	assert.Empty(t, session.AdvisoryLock)
+	assert.Nil(t, session.LockSession)
+
+	// nothing is left to release.
+	sweep()
+	assert.EqualValues(t, 1, sbc1.ReleaseCount.Get())
+}
+
+func TestLockSweeperSkipsBusyConnections(t *testing.T) {
+	executor, sbc1, _, _ := createExecutorEnv()
+	sbc1.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("release_all_locks()", "int64"), "1")})
+
+	start := time.Unix(1000, 0)
+	now := start
+	vh := newVtgateHandler(&VTGate{executor: executor})
+	vh.now = func() time.Time { return now }
+
+	idle := &mysql.Conn{ConnectionID: 1}
+	idle.ClientData = &vtgatepb.Session{
+		AdvisoryLock: map[string]int64{"lock": 1},
+		LockSession: &vtgatepb.Session_ShardSession{
+			Target:      &querypb.Target{Keyspace: "TestExecutor", Shard: "-20", TabletType: topodatapb.TabletType_MASTER},
+			TabletAlias: sbc1.Tablet().Alias,
+			ReservedId:  1,
+		},
+	}
+	vh.startActivity(idle)()
+	// the busy connection waits for the lock of the idle one, e.g. with GET_LOCK('lock', -1).
+	busy := &mysql.Conn{ConnectionID: 2}
+	busy.ClientData = &vtgatepb.Session{}
+	vh.startActivity(busy)()
+	done := vh.startActivity(busy)
+
+	now = start.Add(time.Minute + time.Second)
+	swept := make(chan struct{})
+	go func() {
+		vh.sweepIdleLocks(time.Minute)
+		close(swept)
+	}()
+	select {
+	case <-swept:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the sweep waited for the busy connection")
+	}
+	assert.EqualValues(t, 1, sbc1.ReleaseCount.Get())
+	assert.Empty(t, idle.ClientData.(*vtgatepb.Session).AdvisoryLock)
+
+	// the busy connection can still end its command.
+	done()
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"sync"
+	"time"
+)
+
+// lockTicker calls a function at a fixed interval in its own goroutine, until stopped.
+type lockTicker struct {
+	interval time.Duration
+	// after is the clock of the ticker. It is time.After outside of tests.
+	after func(time.Duration) <-chan time.Time
+	tick  func()
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// startLockTicker starts calling tick every interval, until stopped.
+func startLockTicker(interval time.Duration, after func(time.Duration) <-chan time.Time, tick func()) *lockTicker {
+	t := &lockTicker{
+		interval: interval,
+		after:    after,
+		tick:     tick,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+func (t *lockTicker) run() {
+	defer close(t.done)
+	for {
+		select {
+		case <-t.stop:
+			return
+		case <-t.after(t.interval):
+		}
+		t.tick()
+	}
+}
+
+// Stop stops the ticker and waits for any tick in progress. It can be called more than once.
+func (t *lockTicker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+	<-t.done
+}
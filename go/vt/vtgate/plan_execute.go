@@ -112,6 +112,12 @@ func (e *Executor) newExecute(ctx context.Context, safeSession *SafeSession, sql
 		return 0, nil, err
 	}
 
+	if err := checkPlan(vcursor, plan); err != nil {
+		logStats.Error = err
+		return 0, nil, err
+	}
+	recordPlanWarnings(safeSession, plan)
+
 	if plan.Instructions.NeedsTransaction() {
 		return e.insideTransaction(ctx, safeSession, logStats,
 			e.executePlan(ctx, plan, vcursor, bindVars, execStart))
@@ -193,7 +199,7 @@ func (e *Executor) executePlan(ctx context.Context, plan *engine.Plan, vcursor *
 func (e *Executor) logExecutionEnd(logStats *LogStats, execStart time.Time, plan *engine.Plan, err error, qr *sqltypes.Result) uint64 {
 	logStats.ExecuteTime = time.Since(execStart)
 
-	e.updateQueryCounts(plan.Instructions.RouteType(), plan.Instructions.GetKeyspaceName(), plan.Instructions.GetTableName(), int64(logStats.ShardQueries))
+	e.updateQueryCounts(plan.Instructions.RouteType().String(), plan.Instructions.GetKeyspaceName(), plan.Instructions.GetTableName(), int64(logStats.ShardQueries))
 
 	var errCount uint64
 	if err != nil {
@@ -213,3 +219,25 @@ func (e *Executor) logPlanningFinished(logStats *LogStats, plan *engine.Plan) ti
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
 	return execStart
 }
+
+// recordPlanWarnings records the warnings found while building the plan on the session executing it.
+func recordPlanWarnings(safeSession *SafeSession, plan *engine.Plan) {
+	for _, warning := range plan.Warnings {
+		safeSession.RecordWarning(&querypb.QueryWarning{Message: warning})
+	}
+}
+
+// checkPlan rejects the plans going over the limits set by the flags before any of their queries is executed.
+func checkPlan(vcursor *vcursorImpl, plan *engine.Plan) error {
+	if *reservedConnPrecheck {
+		if err := engine.CheckReservedConn(vcursor, plan.Instructions); err != nil {
+			return err
+		}
+	}
+	if *maxPlanMemory > 0 {
+		if err := engine.CheckMemory(plan.Instructions, *maxPlanMemory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
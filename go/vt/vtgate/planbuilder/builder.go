@@ -46,6 +46,8 @@ type ContextVSchema interface {
 	AnyKeyspace() (*vindexes.Keyspace, error)
 	FirstSortedKeyspace() (*vindexes.Keyspace, error)
 	SysVarSetEnabled() bool
+	AdvisoryLocksEnabled() bool
+	VtgateExplainAnalyzeEnabled() bool
 	KeyspaceExists(keyspace string) bool
 	AllKeyspace() ([]*vindexes.Keyspace, error)
 }
@@ -84,6 +86,9 @@ func BuildFromStmt(query string, stmt sqlparser.Statement, vschema ContextVSchem
 		Instructions: instruction,
 		BindVarNeeds: bindVarNeeds,
 	}
+	if warning := lockOrderWarning(instruction); warning != "" {
+		plan.Warnings = append(plan.Warnings, warning)
+	}
 	return plan, nil
 }
 
@@ -120,6 +125,17 @@ func createInstructionFor(query string, stmt sqlparser.Statement, vschema Contex
 			}
 			return buildExplainPlan(innerInstruction)
 		}
+		if stmt.Type == sqlparser.AnalyzeType && vschema.VtgateExplainAnalyzeEnabled() {
+			switch stmt.Statement.(type) {
+			case *sqlparser.Select, *sqlparser.Union:
+				// the statement is planned and executed by vtgate, which measures each primitive of its plan.
+				innerInstruction, err := createInstructionFor(query, stmt.Statement, vschema)
+				if err != nil {
+					return nil, err
+				}
+				return &engine.ExplainAnalyze{Input: innerInstruction}, nil
+			}
+		}
 		return buildOtherReadAndAdmin(query, vschema)
 	case *sqlparser.OtherRead, *sqlparser.OtherAdmin:
 		return buildOtherReadAndAdmin(query, vschema)
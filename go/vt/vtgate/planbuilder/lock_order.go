@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+// lockOrderWarning returns a warning if the plan acquires several advisory locks
+// in a non canonical order. Sessions acquiring the same locks in different orders
+// can deadlock each other, while sessions always acquiring them in sorted order cannot.
+// The select expressions are not reordered, since that would change the result columns.
+// Locks whose name is only known at execution time are not checked.
+func lockOrderWarning(plan engine.Primitive) string {
+	var names []string
+	engine.Walk(func(p engine.Primitive) bool {
+		l, ok := p.(*engine.Lock)
+		if !ok {
+			return true
+		}
+		for _, lf := range l.LockFuncs {
+			if lf.Type != engine.GetLock || lf.Name.Value.IsNull() {
+				continue
+			}
+			names = append(names, lf.Name.Value.ToString())
+		}
+		return true
+	}, plan)
+	if sort.StringsAreSorted(names) {
+		return ""
+	}
+	return fmt.Sprintf("advisory locks are not acquired in sorted order, which can lead to deadlocks: %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtgate/engine"
+)
+
+func TestLockOrderWarning(t *testing.T) {
+	vschema := &vschemaWrapper{
+		v: loadSchema(t, "schema_test.json"),
+	}
+	build := func(query string) engine.Primitive {
+		plan, err := TestBuilder(query, vschema)
+		require.NoError(t, err)
+		return plan.Instructions
+	}
+
+	assert.Empty(t, lockOrderWarning(build("select get_lock('a', 10), get_lock('b', 10) from dual")))
+	assert.Empty(t, lockOrderWarning(build("select get_lock(:name, 10), get_lock('a', 10) from dual")))
+	assert.Equal(t,
+		"advisory locks are not acquired in sorted order, which can lead to deadlocks: b, a",
+		lockOrderWarning(build("select get_lock('b', 10), release_lock('c'), get_lock('a', 10) from dual")))
+
+	// locks are collected across the whole plan.
+	concat := &engine.Concatenate{Sources: []engine.Primitive{
+		build("select get_lock('b', 10) from dual"),
+		build("select get_lock('a', 10) from dual"),
+	}}
+	assert.Equal(t,
+		"advisory locks are not acquired in sorted order, which can lead to deadlocks: b, a",
+		lockOrderWarning(concat))
+
+	// the warning is kept on the plan, so that cached plans carry it too.
+	plan, err := TestBuilder("select get_lock('b', 10), get_lock('a', 10) from dual", vschema)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"advisory locks are not acquired in sorted order, which can lead to deadlocks: b, a"}, plan.Warnings)
+}
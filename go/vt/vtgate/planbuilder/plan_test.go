@@ -195,6 +195,18 @@ func TestSysVarSetDisabled(t *testing.T) {
 	testFile(t, "set_sysvar_disabled_cases.txt", testOutputTempDir, vschemaWrapper)
 }
 
+func TestAdvisoryLocksDisabled(t *testing.T) {
+	vschemaWrapper := &vschemaWrapper{
+		v:                     loadSchema(t, "schema_test.json"),
+		advisoryLocksDisabled: true,
+	}
+
+	testOutputTempDir, err := ioutil.TempDir("", "plan_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(testOutputTempDir)
+	testFile(t, "lock_disabled_cases.txt", testOutputTempDir, vschemaWrapper)
+}
+
 func TestOne(t *testing.T) {
 	vschema := &vschemaWrapper{
 		v: loadSchema(t, "schema_test.json"),
@@ -255,6 +267,9 @@ func TestOtherPlanningFromFile(t *testing.T) {
 
 	testFile(t, "other_read_cases.txt", testOutputTempDir, vschema)
 	testFile(t, "other_admin_cases.txt", testOutputTempDir, vschema)
+
+	vschema.vtgateExplainAnalyze = true
+	testFile(t, "vtgate_explain_analyze_cases.txt", testOutputTempDir, vschema)
 }
 
 func loadSchema(t *testing.T, filename string) *vindexes.VSchema {
@@ -282,6 +297,9 @@ type vschemaWrapper struct {
 	tabletType    topodatapb.TabletType
 	dest          key.Destination
 	sysVarEnabled bool
+	// advisoryLocksDisabled is the opposite of AdvisoryLocksEnabled, so that the locks are enabled by default.
+	advisoryLocksDisabled bool
+	vtgateExplainAnalyze  bool
 }
 
 func (vw *vschemaWrapper) AllKeyspace() ([]*vindexes.Keyspace, error) {
@@ -302,6 +320,14 @@ func (vw *vschemaWrapper) SysVarSetEnabled() bool {
 	return vw.sysVarEnabled
 }
 
+func (vw *vschemaWrapper) AdvisoryLocksEnabled() bool {
+	return !vw.advisoryLocksDisabled
+}
+
+func (vw *vschemaWrapper) VtgateExplainAnalyzeEnabled() bool {
+	return vw.vtgateExplainAnalyze
+}
+
 func (vw *vschemaWrapper) TargetDestination(qualifier string) (key.Destination, *vindexes.Keyspace, topodatapb.TabletType, error) {
 	var keyspaceName string
 	if vw.keyspace != nil {
@@ -283,15 +283,62 @@ func handleDualSelects(sel *sqlparser.Select, vschema ContextVSchema) (engine.Pr
 }
 
 func buildLockingPrimitive(sel *sqlparser.Select, vschema ContextVSchema) (engine.Primitive, error) {
+	if !vschema.AdvisoryLocksEnabled() {
+		return nil, vterrors.New(vtrpcpb.Code_UNIMPLEMENTED, "advisory locks are disabled")
+	}
 	ks, err := vschema.FirstSortedKeyspace()
 	if err != nil {
 		return nil, err
 	}
-	return &engine.Lock{
-		Keyspace:          ks,
-		TargetDestination: key.DestinationKeyspaceID{0},
-		Query:             sqlparser.String(sel),
-	}, nil
+	return engine.NewLock(ks, key.DestinationKeyspaceID{0}, sqlparser.String(sel), engine.WithLockFuncs(lockFuncs(sel)...))
+}
+
+var lockFuncTypes = map[string]engine.LockFuncType{
+	"get_lock":          engine.GetLock,
+	"is_free_lock":      engine.IsFreeLock,
+	"is_used_lock":      engine.IsUsedLock,
+	"release_all_locks": engine.ReleaseAllLocks,
+	"release_lock":      engine.ReleaseLock,
+}
+
+// lockFuncs returns the locking functions selected by the query, along with their column and its name.
+// Functions whose lock name cannot be evaluated by vtgate are skipped.
+func lockFuncs(sel *sqlparser.Select) []engine.LockFunc {
+	var funcs []engine.LockFunc
+	for i, node := range sel.SelectExprs {
+		expr, ok := node.(*sqlparser.AliasedExpr)
+		if !ok {
+			continue
+		}
+		fn, ok := expr.Expr.(*sqlparser.FuncExpr)
+		if !ok {
+			continue
+		}
+		typ, ok := lockFuncTypes[fn.Name.Lowered()]
+		if !ok {
+			continue
+		}
+		lf := engine.LockFunc{Type: typ, Column: i, ColumnName: expr.As.String()}
+		if lf.ColumnName == "" {
+			lf.ColumnName = sqlparser.String(expr.Expr)
+		}
+		if typ != engine.ReleaseAllLocks {
+			if len(fn.Exprs) == 0 {
+				continue
+			}
+			arg, ok := fn.Exprs[0].(*sqlparser.AliasedExpr)
+			if !ok {
+				continue
+			}
+			pv, err := sqlparser.NewPlanValue(arg.Expr)
+			if err != nil {
+				continue
+			}
+			lf.Name = pv
+		}
+		funcs = append(funcs, lf)
+	}
+	return funcs
 }
 
 func isOnlyDual(sel *sqlparser.Select) bool {
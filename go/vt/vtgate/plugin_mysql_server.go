@@ -82,12 +82,18 @@ type vtgateHandler struct {
 
 	vtg         *VTGate
 	connections map[*mysql.Conn]bool
+	// activity tracks the idle time of the connections, for the lock sweeper.
+	activity map[*mysql.Conn]*connActivity
+	// now is the clock of the handler. It is time.Now outside of tests.
+	now func() time.Time
 }
 
 func newVtgateHandler(vtg *VTGate) *vtgateHandler {
 	return &vtgateHandler{
 		vtg:         vtg,
 		connections: make(map[*mysql.Conn]bool),
+		activity:    make(map[*mysql.Conn]*connActivity),
+		now:         time.Now,
 	}
 }
 
@@ -104,6 +110,7 @@ func (vh *vtgateHandler) numConnections() int {
 }
 
 func (vh *vtgateHandler) ComResetConnection(c *mysql.Conn) {
+	defer vh.startActivity(c)()
 	ctx := context.Background()
 	session := vh.session(c)
 	if session.InTransaction {
@@ -121,7 +128,9 @@ func (vh *vtgateHandler) ConnectionClosed(c *mysql.Conn) {
 		vh.mu.Lock()
 		defer vh.mu.Unlock()
 		delete(vh.connections, c)
+		delete(vh.activity, c)
 	}()
+	defer vh.startActivity(c)()
 
 	var ctx context.Context
 	var cancel context.CancelFunc
@@ -173,6 +182,7 @@ func startSpan(ctx context.Context, query, label string) (trace.Span, context.Co
 }
 
 func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	defer vh.startActivity(c)()
 	ctx := context.Background()
 	var cancel context.CancelFunc
 	if *mysqlQueryTimeout != 0 {
@@ -238,6 +248,7 @@ func fillInTxStatusFlags(c *mysql.Conn, session *vtgatepb.Session) {
 
 // ComPrepare is the handler for command prepare.
 func (vh *vtgateHandler) ComPrepare(c *mysql.Conn, query string, bindVars map[string]*querypb.BindVariable) ([]*querypb.Field, error) {
+	defer vh.startActivity(c)()
 	var ctx context.Context
 	var cancel context.CancelFunc
 	if *mysqlQueryTimeout != 0 {
@@ -280,6 +291,7 @@ func (vh *vtgateHandler) ComPrepare(c *mysql.Conn, query string, bindVars map[st
 }
 
 func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	defer vh.startActivity(c)()
 	var ctx context.Context
 	var cancel context.CancelFunc
 	if *mysqlQueryTimeout != 0 {
@@ -354,6 +366,8 @@ var mysqlListener *mysql.Listener
 var mysqlUnixListener *mysql.Listener
 var sigChan chan os.Signal
 var vtgateHandle *vtgateHandler
+var idleLockSweeper *lockTicker
+var idleLockHeartbeat *lockTicker
 
 // initTLSConfig inits tls config for the given mysql listener
 func initTLSConfig(mysqlListener *mysql.Listener, mysqlSslCert, mysqlSslKey, mysqlSslCa string, mysqlServerRequireSecureTransport bool) error {
@@ -415,6 +429,12 @@ func initMySQLProtocol() {
 	// Create a Listener.
 	var err error
 	vtgateHandle = newVtgateHandler(rpcVTGate)
+	if *advisoryLockIdleTimeout > 0 {
+		idleLockSweeper = startLockSweeper(vtgateHandle, *advisoryLockIdleTimeout, time.After)
+	}
+	if *lockHeartbeatTime > 0 {
+		idleLockHeartbeat = startLockTicker(*lockHeartbeatTime, time.After, vtgateHandle.heartbeatIdleLocks)
+	}
 	if *mysqlServerPort >= 0 {
 		mysqlListener, err = mysql.NewListener(*mysqlTCPVersion, net.JoinHostPort(*mysqlServerBindAddress, fmt.Sprintf("%v", *mysqlServerPort)), authServer, vtgateHandle, *mysqlConnReadTimeout, *mysqlConnWriteTimeout, *mysqlProxyProtocol)
 		if err != nil {
@@ -483,6 +503,14 @@ func newMysqlUnixSocket(address string, authServer mysql.AuthServer, handler mys
 }
 
 func shutdownMysqlProtocolAndDrain() {
+	if idleLockSweeper != nil {
+		idleLockSweeper.Stop()
+		idleLockSweeper = nil
+	}
+	if idleLockHeartbeat != nil {
+		idleLockHeartbeat.Stop()
+		idleLockHeartbeat = nil
+	}
 	if mysqlListener != nil {
 		mysqlListener.Close()
 		mysqlListener = nil
@@ -508,6 +536,10 @@ func shutdownMysqlProtocolAndDrain() {
 			time.Sleep(1 * time.Millisecond)
 		}
 	}
+
+	if vtgateHandle != nil {
+		vtgateHandle.releaseAllLocks(*lockReleaseShutdownTimeout)
+	}
 }
 
 func rollbackAtShutdown() {
@@ -21,6 +21,7 @@ import (
 	"net/http"
 
 	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 )
 
 var (
@@ -38,6 +39,12 @@ var (
 
 	// queryLogToFile controls whether query logs are sent to a file
 	queryLogToFile = flag.String("log_queries_to_file", "", "Enable query logging to the specified file")
+
+	// LockAuditLogHandler is the debug UI path for exposing the advisory lock audit log
+	LockAuditLogHandler = "/debug/lockauditlog"
+
+	// lockAuditLogToFile controls whether the advisory lock audit log is sent to a file
+	lockAuditLogToFile = flag.String("log_lock_audit_to_file", "", "Enable the advisory lock audit log to the specified file")
 )
 
 func initQueryLogger(vtg *VTGate) error {
@@ -60,5 +67,13 @@ func initQueryLogger(vtg *VTGate) error {
 		}
 	}
 
+	engine.LockAuditLogger.ServeLogs(LockAuditLogHandler, streamlog.GetFormatter(engine.LockAuditLogger))
+	if *lockAuditLogToFile != "" {
+		_, err := engine.LockAuditLogger.LogToFile(*lockAuditLogToFile, streamlog.GetFormatter(engine.LockAuditLogger))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
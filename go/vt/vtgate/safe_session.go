@@ -22,7 +22,9 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -43,6 +45,7 @@ type SafeSession struct {
 	// this is a signal that found_rows has already been handles by the primitives,
 	// and doesn't have to be updated by the executor
 	foundRowsHandled bool
+
 	*vtgatepb.Session
 }
 
@@ -100,6 +103,7 @@ func (session *SafeSession) ResetTx() {
 	session.Session.InTransaction = false
 	session.commitOrder = vtgatepb.CommitOrder_NORMAL
 	session.Savepoints = nil
+	session.TxAdvisoryLock = nil
 	if !session.Session.InReservedConn {
 		session.ShardSessions = nil
 		session.PreSessions = nil
@@ -116,6 +120,7 @@ func (session *SafeSession) Reset() {
 	session.Session.InTransaction = false
 	session.commitOrder = vtgatepb.CommitOrder_NORMAL
 	session.Savepoints = nil
+	session.TxAdvisoryLock = nil
 	session.ShardSessions = nil
 	session.PreSessions = nil
 	session.PostSessions = nil
@@ -399,11 +404,52 @@ func (session *SafeSession) InLockSession() bool {
 	return session.LockSession != nil
 }
 
+// LockKey returns a value identifying the connection holding the advisory locks of the session,
+// or nil if there is none. Unlike the session proto, which gRPC clients send again with each
+// request, it stays the same across the queries of the session.
+func (session *SafeSession) LockKey() interface{} {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	ls := session.LockSession
+	if ls == nil || ls.ReservedId == 0 {
+		return nil
+	}
+	return fmt.Sprintf("%s/%s/%s/%d", ls.Target.GetKeyspace(), ls.Target.GetShard(), topoproto.TabletAliasString(ls.TabletAlias), ls.ReservedId)
+}
+
 // ResetLock resets the lock session
 func (session *SafeSession) ResetLock() {
+	key := session.LockKey()
 	session.mu.Lock()
-	defer session.mu.Unlock()
 	session.LockSession = nil
+	session.AdvisoryLock = nil
+	session.resetLockConnID()
+	session.mu.Unlock()
+	engine.AllLocksReleased(key)
+}
+
+// ResetLockConnection resets the lock session after its connection was lost.
+// The advisory locks of the session are kept, so that LockLost reports them.
+func (session *SafeSession) ResetLockConnection() {
+	key := session.LockKey()
+	session.mu.Lock()
+	session.LockSession = nil
+	session.resetLockConnID()
+	session.mu.Unlock()
+	engine.LockConnectionLost(key)
+}
+
+// resetLockConnID forgets the lock connection id reported to the session, if any:
+// the session has no lock connection anymore.
+func (session *SafeSession) resetLockConnID() {
+	delete(session.UserDefinedVariables, engine.LockConnIDVariable)
+}
+
+// LockLost returns true if the session holds advisory locks without a connection backing them.
+func (session *SafeSession) LockLost() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.LockSession == nil && len(session.AdvisoryLock) > 0
 }
 
 // ResetAll resets the shard sessions and lock session.
@@ -419,6 +465,134 @@ func (session *SafeSession) ResetAll() {
 	session.PreSessions = nil
 	session.PostSessions = nil
 	session.LockSession = nil
+	session.AdvisoryLock = nil
+	session.resetLockConnID()
+}
+
+// AdvisoryLockCount returns the number of times the session acquired the named advisory lock.
+func (session *SafeSession) AdvisoryLockCount(name string) int64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.AdvisoryLock[name]
+}
+
+// AdvisoryLocksHeld returns the number of distinct advisory locks held by the session.
+func (session *SafeSession) AdvisoryLocksHeld() int {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return len(session.AdvisoryLock)
+}
+
+// AdvisoryLocks returns a copy of the advisory locks held by the session,
+// along with the number of times each was acquired.
+func (session *SafeSession) AdvisoryLocks() map[string]int64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if len(session.AdvisoryLock) == 0 {
+		return nil
+	}
+	locks := make(map[string]int64, len(session.AdvisoryLock))
+	for name, count := range session.AdvisoryLock {
+		locks[name] = count
+	}
+	return locks
+}
+
+// LockSessionTarget returns the target of the connection holding the advisory locks, if any.
+func (session *SafeSession) LockSessionTarget() *querypb.Target {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.LockSession == nil {
+		return nil
+	}
+	return session.LockSession.Target
+}
+
+// LockSessionReservedID returns the id of the reserved connection holding the advisory locks, or 0 if there is none.
+func (session *SafeSession) LockSessionReservedID() int64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.LockSession == nil {
+		return 0
+	}
+	return session.LockSession.ReservedId
+}
+
+// SetAdvisoryLockCount sets the number of times the session acquired the named advisory lock.
+// A count of zero or less removes the lock from the session.
+// The locks acquired during a transaction are also recorded, to release them on commit if requested.
+func (session *SafeSession) SetAdvisoryLockCount(name string, count int64) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.setTxAdvisoryLockCount(name, count)
+	if count <= 0 {
+		delete(session.AdvisoryLock, name)
+		if len(session.AdvisoryLock) == 0 {
+			session.AdvisoryLock = nil
+		}
+		return
+	}
+	if session.AdvisoryLock == nil {
+		session.AdvisoryLock = make(map[string]int64)
+	}
+	session.AdvisoryLock[name] = count
+}
+
+// ResetAdvisoryLocks removes all the advisory locks from the session.
+func (session *SafeSession) ResetAdvisoryLocks() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.AdvisoryLock = nil
+	session.TxAdvisoryLock = nil
+}
+
+// setTxAdvisoryLockCount updates the number of times the lock was acquired during the transaction.
+// It must be called with the mutex held, before the session count is updated.
+func (session *SafeSession) setTxAdvisoryLockCount(name string, count int64) {
+	if !session.Session.InTransaction && session.TxAdvisoryLock == nil {
+		return
+	}
+	txCount := session.TxAdvisoryLock[name]
+	if session.Session.InTransaction && count > session.AdvisoryLock[name] {
+		txCount += count - session.AdvisoryLock[name]
+	}
+	// locks acquired before the transaction are released first.
+	if txCount > count {
+		txCount = count
+	}
+	if txCount <= 0 {
+		delete(session.TxAdvisoryLock, name)
+		if len(session.TxAdvisoryLock) == 0 {
+			session.TxAdvisoryLock = nil
+		}
+		return
+	}
+	if session.TxAdvisoryLock == nil {
+		session.TxAdvisoryLock = make(map[string]int64)
+	}
+	session.TxAdvisoryLock[name] = txCount
+}
+
+// SetReleaseLocksOnCommit sets whether the advisory locks acquired during a transaction are released on commit.
+func (session *SafeSession) SetReleaseLocksOnCommit(release bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.ReleaseLocksOnCommit = release
+}
+
+// TxAdvisoryLocks returns a copy of the advisory locks acquired during the transaction
+// if they must be released on commit, along with the number of times each was acquired.
+func (session *SafeSession) TxAdvisoryLocks() map[string]int64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if !session.ReleaseLocksOnCommit || len(session.TxAdvisoryLock) == 0 {
+		return nil
+	}
+	locks := make(map[string]int64, len(session.TxAdvisoryLock))
+	for name, count := range session.TxAdvisoryLock {
+		locks[name] = count
+	}
+	return locks
 }
 
 // ResetShard reset the shard session for the provided tablet alias.
@@ -21,9 +21,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 )
 
 func TestFailToMultiShardWhenSetToSingleDb(t *testing.T) {
@@ -66,3 +68,35 @@ func TestPrequeries(t *testing.T) {
 		t.Errorf("got %v but wanted %v", preQueries, want)
 	}
 }
+
+func TestResetLockConnID(t *testing.T) {
+	session := NewSafeSession(&vtgatepb.Session{
+		LockSession: &vtgatepb.Session_ShardSession{ReservedId: 7},
+		UserDefinedVariables: map[string]*querypb.BindVariable{
+			"x":                       sqltypes.Int64BindVariable(1),
+			engine.LockConnIDVariable: sqltypes.Int64BindVariable(7),
+		},
+	})
+	// the reported id goes away with the lock connection, the other variables stay.
+	session.ResetLock()
+	require.Equal(t, map[string]*querypb.BindVariable{"x": sqltypes.Int64BindVariable(1)}, session.UserDefinedVariables)
+}
+
+func TestLockKey(t *testing.T) {
+	lockSession := func() *vtgatepb.Session_ShardSession {
+		return &vtgatepb.Session_ShardSession{
+			Target:      &querypb.Target{Keyspace: "ks", Shard: "-80"},
+			TabletAlias: &topodatapb.TabletAlias{Cell: "cell", Uid: 1},
+			ReservedId:  7,
+		}
+	}
+	// gRPC clients send a new session proto with each request: the key must not depend on it.
+	session1 := NewSafeSession(&vtgatepb.Session{LockSession: lockSession()})
+	session2 := NewSafeSession(&vtgatepb.Session{LockSession: lockSession()})
+	require.Equal(t, "ks/-80/cell-0000000001/7", session1.LockKey())
+	require.Equal(t, session1.LockKey(), session2.LockKey())
+
+	session1.ResetLockConnection()
+	require.Nil(t, session1.LockKey())
+	require.Nil(t, NewSafeSession(&vtgatepb.Session{}).LockKey())
+}
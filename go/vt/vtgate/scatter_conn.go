@@ -170,14 +170,7 @@ func (stc *ScatterConn) ExecuteMultiShard(
 
 	if session.InLockSession() && session.TriggerLockHeartBeat() {
 		go func() {
-			_, lockErr := stc.ExecuteLock(ctx, &srvtopo.ResolvedShard{
-				Target:  session.LockSession.Target,
-				Gateway: stc.gateway,
-			}, &querypb.BoundQuery{
-				Sql:           "select 1",
-				BindVariables: nil,
-			}, session)
-			if lockErr != nil {
+			if lockErr := stc.lockHeartbeat(ctx, session); lockErr != nil {
 				log.Warningf("Locking heartbeat failed, held locks might be released: %s", lockErr.Error())
 			}
 		}()
@@ -613,6 +606,19 @@ func (stc *ScatterConn) ExecuteLock(
 		return nil, vterrors.New(vtrpcpb.Code_INTERNAL, "session cannot be nil")
 	}
 
+	// The connection holding the locks was lost since the last lock query, e.g. during a heartbeat.
+	// The client must not carry on as if it still held them.
+	if session.LockLost() {
+		if !*reacquireLocks {
+			session.ResetLock()
+			return nil, vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "advisory lock connection lost; lock no longer held")
+		}
+		if err := stc.reacquireLocks(ctx, rs, session); err != nil {
+			session.ResetLock()
+			return nil, err
+		}
+	}
+
 	opts = session.Session.Options
 	info, err := lockInfo(rs.Target, session)
 	// Lock session is created on alphabetic sorted keyspace.
@@ -636,7 +642,7 @@ func (stc *ScatterConn) ExecuteLock(
 		}
 		qr, err = qs.Execute(ctx, rs.Target, query.Sql, query.BindVariables, 0 /* transactionID */, reservedID, opts)
 		if err != nil && wasConnectionClosed(err) {
-			session.ResetLock()
+			session.ResetLockConnection()
 			err = vterrors.Wrap(err, "held locks released")
 		}
 		session.UpdateLockHeartbeat()
@@ -663,6 +669,18 @@ func (stc *ScatterConn) ExecuteLock(
 	return qr, err
 }
 
+// lockHeartbeat keeps the lock connection of the session active.
+func (stc *ScatterConn) lockHeartbeat(ctx context.Context, session *SafeSession) error {
+	_, err := stc.ExecuteLock(ctx, &srvtopo.ResolvedShard{
+		Target:  session.LockSession.Target,
+		Gateway: stc.gateway,
+	}, &querypb.BoundQuery{
+		Sql:           "select 1",
+		BindVariables: nil,
+	}, session)
+	return err
+}
+
 func wasConnectionClosed(err error) bool {
 	sqlErr := mysql.NewSQLErrorFromError(err).(*mysql.SQLError)
 
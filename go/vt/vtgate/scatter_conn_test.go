@@ -17,6 +17,7 @@ limitations under the License.
 package vtgate
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -302,3 +303,43 @@ func TestReservedConnFail(t *testing.T) {
 	require.Equal(t, 1, len(session.ShardSessions))
 	assert.NotEqual(t, oldRId, session.Session.ShardSessions[0].ReservedId, "should have recreated a reserved connection since the last connection was lost")
 }
+
+func TestLockConnectionLost(t *testing.T) {
+	keyspace := "keyspace"
+	createSandbox(keyspace)
+	hc := discovery.NewFakeHealthCheck()
+	sc := newTestScatterConn(hc, new(sandboxTopo), "aa")
+	sbc0 := hc.AddTestTablet("aa", "0", 1, keyspace, "0", topodatapb.TabletType_MASTER, true, 1, nil)
+	rs := &srvtopo.ResolvedShard{
+		Target:  &querypb.Target{Keyspace: keyspace, Shard: "0", TabletType: topodatapb.TabletType_MASTER},
+		Gateway: sc.gateway,
+	}
+	getLock := &querypb.BoundQuery{Sql: "select get_lock('lock name', 10) from dual"}
+	ctx := context.Background()
+
+	session := NewSafeSession(&vtgatepb.Session{})
+	_, err := sc.ExecuteLock(ctx, rs, getLock, session)
+	require.NoError(t, err)
+	require.True(t, session.InLockSession())
+	session.SetAdvisoryLockCount("lock name", 1)
+
+	// the reserved connection is lost, e.g. while sending the lock heartbeat.
+	sbc0.EphemeralShardErr = mysql.NewSQLError(mysql.CRServerGone, mysql.SSUnknownSQLState, "lost connection")
+	_, err = sc.ExecuteLock(ctx, rs, &querypb.BoundQuery{Sql: "select 1"}, session)
+	require.Error(t, err)
+	assert.False(t, session.InLockSession())
+	assert.True(t, session.LockLost())
+
+	// the next lock query must not silently reserve a new connection.
+	_, err = sc.ExecuteLock(ctx, rs, getLock, session)
+	require.EqualError(t, err, "advisory lock connection lost; lock no longer held")
+	assert.False(t, session.LockLost())
+	assert.Nil(t, session.AdvisoryLock)
+	assert.EqualValues(t, 1, sbc0.ReserveCount.Get())
+
+	// once the loss is reported, locks can be acquired again.
+	_, err = sc.ExecuteLock(ctx, rs, getLock, session)
+	require.NoError(t, err)
+	assert.True(t, session.InLockSession())
+	assert.EqualValues(t, 2, sbc0.ReserveCount.Get())
+}
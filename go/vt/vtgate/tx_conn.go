@@ -18,7 +18,9 @@ package vtgate
 
 import (
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 
@@ -26,13 +28,17 @@ import (
 
 	"golang.org/x/net/context"
 
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/concurrency"
 	"vitess.io/vitess/go/vt/dtids"
 	"vitess.io/vitess/go/vt/log"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
 // TxConn is used for executing transactional requests.
@@ -76,10 +82,54 @@ func (txc *TxConn) Commit(ctx context.Context, session *SafeSession) error {
 	case vtgatepb.TransactionMode_UNSPECIFIED:
 		twopc = txc.mode == vtgatepb.TransactionMode_TWOPC
 	}
+	var err error
 	if twopc {
-		return txc.commit2PC(ctx, session)
+		err = txc.commit2PC(ctx, session)
+	} else {
+		err = txc.commitNormal(ctx, session)
+	}
+	if err != nil {
+		return err
+	}
+	if err := txc.releaseTxLocks(ctx, session); err != nil {
+		// The transaction is committed: failing the commit would make the client retry it.
+		log.Warningf("Releasing the advisory locks of the transaction failed on commit: %v", err)
+		session.RecordWarning(&querypb.QueryWarning{Message: fmt.Sprintf("advisory locks of the transaction are still held: %v", err)})
+	}
+	return nil
+}
+
+// releaseTxLocks releases the advisory locks acquired during the transaction,
+// if the session enabled release_locks_on_commit.
+func (txc *TxConn) releaseTxLocks(ctx context.Context, session *SafeSession) error {
+	locks := session.TxAdvisoryLocks()
+	if len(locks) == 0 || !session.InLockSession() {
+		return nil
+	}
+	ls := session.LockSession
+	qs, err := txc.queryService(ls.TabletAlias)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(locks))
+	for name := range locks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for count := locks[name]; count > 0; count-- {
+			bindVars := map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable(name)}
+			if _, err := qs.Execute(ctx, ls.Target, "select release_lock(:name) from dual", bindVars, 0 /* transactionID */, ls.ReservedId, session.Options); err != nil {
+				return vterrors.Wrapf(err, "failed to release lock %s on commit", name)
+			}
+			remaining := session.AdvisoryLockCount(name) - 1
+			session.SetAdvisoryLockCount(name, remaining)
+			if remaining <= 0 {
+				engine.LockReleased(session.LockKey(), ls.Target, name)
+			}
+		}
 	}
-	return txc.commitNormal(ctx, session)
+	return nil
 }
 
 func (txc *TxConn) queryService(alias *topodatapb.TabletAlias) (queryservice.QueryService, error) {
@@ -265,6 +315,7 @@ func (txc *TxConn) ReleaseLock(ctx context.Context, session *SafeSession) error
 	if err != nil {
 		return err
 	}
+	cleanupLockConn(ctx, qs, ls, session.Options)
 	err = qs.Release(ctx, ls.Target, 0, ls.ReservedId)
 	if err != nil {
 		return err
@@ -274,6 +325,17 @@ func (txc *TxConn) ReleaseLock(ctx context.Context, session *SafeSession) error
 
 }
 
+// cleanupLockConn sends the lock_connection_cleanup_query, if set, to the lock connection
+// about to be released. A failure is only logged: the connection is released regardless.
+func cleanupLockConn(ctx context.Context, qs queryservice.QueryService, ls *vtgatepb.Session_ShardSession, options *querypb.ExecuteOptions) {
+	if *lockConnCleanupQuery == "" || ls.ReservedId == 0 {
+		return
+	}
+	if _, err := qs.Execute(ctx, ls.Target, *lockConnCleanupQuery, nil, 0 /* transactionID */, ls.ReservedId, options); err != nil {
+		log.Warningf("Lock connection cleanup failed on %s: %v", topoproto.TabletAliasString(ls.TabletAlias), err)
+	}
+}
+
 //ReleaseAll releases all the shard sessions and lock session.
 func (txc *TxConn) ReleaseAll(ctx context.Context, session *SafeSession) error {
 	if !session.InTransaction() && !session.InReservedConn() && !session.InLockSession() {
@@ -295,6 +357,9 @@ func (txc *TxConn) ReleaseAll(ctx context.Context, session *SafeSession) error {
 		if err != nil {
 			return err
 		}
+		if s == session.LockSession {
+			cleanupLockConn(ctx, qs, s, session.Options)
+		}
 		err = qs.Release(ctx, s.Target, s.TransactionId, s.ReservedId)
 		if err != nil {
 			return err
@@ -305,6 +370,52 @@ func (txc *TxConn) ReleaseAll(ctx context.Context, session *SafeSession) error {
 	})
 }
 
+// ReleaseAllEverywhere issues RELEASE_ALL_LOCKS on every reserved connection of the session,
+// and returns the total number of locks released. Locks are normally held by the lock session
+// only, but a session can end up holding some on other reserved connections, e.g. after
+// reconnecting. It is the cleanup used when the session is closed.
+func (txc *TxConn) ReleaseAllEverywhere(ctx context.Context, session *SafeSession) (int64, error) {
+	var reserved []*vtgatepb.Session_ShardSession
+	allsessions := append(append(append([]*vtgatepb.Session_ShardSession(nil), session.PreSessions...), session.ShardSessions...), session.PostSessions...)
+	if session.LockSession != nil {
+		allsessions = append(allsessions, session.LockSession)
+	}
+	for _, s := range allsessions {
+		if s.ReservedId != 0 {
+			reserved = append(reserved, s)
+		}
+	}
+	if len(reserved) == 0 {
+		return 0, nil
+	}
+
+	var released int64
+	err := txc.runSessions(ctx, reserved, func(ctx context.Context, s *vtgatepb.Session_ShardSession) error {
+		qs, err := txc.queryService(s.TabletAlias)
+		if err != nil {
+			return err
+		}
+		qr, err := qs.Execute(ctx, s.Target, "select release_all_locks() from dual", nil, s.TransactionId, s.ReservedId, session.Options)
+		if err != nil {
+			return vterrors.Wrapf(err, "failed to release the locks of %s", topoproto.TabletAliasString(s.TabletAlias))
+		}
+		if len(qr.Rows) == 1 && len(qr.Rows[0]) == 1 {
+			count, err := evalengine.ToInt64(qr.Rows[0][0])
+			if err != nil {
+				return err
+			}
+			atomic.AddInt64(&released, count)
+		}
+		return nil
+	})
+	if err != nil {
+		return released, err
+	}
+	session.ResetAdvisoryLocks()
+	engine.AllLocksReleased(session.LockKey())
+	return released, nil
+}
+
 // Resolve resolves the specified 2PC transaction.
 func (txc *TxConn) Resolve(ctx context.Context, dtid string) error {
 	mmShard, err := dtids.ShardSession(dtid)
@@ -17,6 +17,7 @@ limitations under the License.
 package vtgate
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,6 +26,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/srvtopo"
@@ -1213,3 +1215,133 @@ func newTestTxConnEnv(t *testing.T, name string) (sc *ScatterConn, sbc0, sbc1 *s
 	require.NoError(t, err)
 	return sc, sbc0, sbc1, rss0, rss1, rss01
 }
+
+func TestTxConnReleaseLocksOnCommit(t *testing.T) {
+	for _, release := range []bool{false, true} {
+		t.Run(fmt.Sprintf("release_locks_on_commit=%v", release), func(t *testing.T) {
+			sc, sbc0, _, rss0, _, _ := newTestTxConnEnv(t, "TestTxConn")
+			session := NewSafeSession(&vtgatepb.Session{InTransaction: true})
+			session.SetReleaseLocksOnCommit(release)
+
+			// "before" is acquired before the transaction starts, "during" is acquired twice during the transaction.
+			session.Session.InTransaction = false
+			_, err := sc.ExecuteLock(ctx, rss0[0], &querypb.BoundQuery{Sql: "select get_lock('before', 10) from dual"}, session)
+			require.NoError(t, err)
+			session.SetAdvisoryLockCount("before", 1)
+			session.Session.InTransaction = true
+			sc.ExecuteMultiShard(ctx, rss0, queries, session, false, false)
+			session.SetAdvisoryLockCount("during", 1)
+			session.SetAdvisoryLockCount("during", 2)
+			assert.Equal(t, map[string]int64{"during": 2}, session.TxAdvisoryLock)
+			sbc0.Queries = nil
+
+			require.NoError(t, sc.txConn.Commit(ctx, session))
+			assert.Nil(t, session.TxAdvisoryLock)
+			if !release {
+				assert.Equal(t, map[string]int64{"before": 1, "during": 2}, session.AdvisoryLock)
+				assert.Empty(t, sbc0.Queries)
+				return
+			}
+			assert.Equal(t, map[string]int64{"before": 1}, session.AdvisoryLock)
+			releaseQuery := &querypb.BoundQuery{
+				Sql:           "select release_lock(:name) from dual",
+				BindVariables: map[string]*querypb.BindVariable{"name": sqltypes.StringBindVariable("during")},
+			}
+			utils.MustMatch(t, []*querypb.BoundQuery{releaseQuery, releaseQuery}, sbc0.Queries, "")
+		})
+	}
+}
+
+func TestTxConnReleaseLocksOnCommitError(t *testing.T) {
+	sc, sbc0, sbc1, rss0, rss1, _ := newTestTxConnEnv(t, "TestTxConn")
+	session := NewSafeSession(&vtgatepb.Session{})
+	session.SetReleaseLocksOnCommit(true)
+	_, err := sc.ExecuteLock(ctx, rss1[0], &querypb.BoundQuery{Sql: "select get_lock('lock name', 10) from dual"}, session)
+	require.NoError(t, err)
+	session.Session.InTransaction = true
+	sc.ExecuteMultiShard(ctx, rss0, queries, session, false, false)
+	session.SetAdvisoryLockCount("lock name", 1)
+	sbc1.MustFailCodes[vtrpcpb.Code_UNAVAILABLE] = 1
+
+	// the transaction is committed, so the commit succeeds and warns about the locks still held.
+	require.NoError(t, sc.txConn.Commit(ctx, session))
+	assert.EqualValues(t, 1, sbc0.CommitCount.Get())
+	assert.Equal(t, map[string]int64{"lock name": 1}, session.AdvisoryLock)
+	require.Len(t, session.Warnings, 1)
+	assert.Contains(t, session.Warnings[0].Message, "failed to release lock lock name on commit")
+}
+
+func TestTxConnReleaseAllEverywhere(t *testing.T) {
+	sc, sbc0, sbc1, rss0, rss1, _ := newTestTxConnEnv(t, "TestTxConn")
+	session := NewSafeSession(&vtgatepb.Session{
+		ShardSessions: []*vtgatepb.Session_ShardSession{{
+			Target:      rss0[0].Target,
+			TabletAlias: sbc0.Tablet().Alias,
+			ReservedId:  1,
+		}},
+		LockSession: &vtgatepb.Session_ShardSession{
+			Target:      rss1[0].Target,
+			TabletAlias: sbc1.Tablet().Alias,
+			ReservedId:  2,
+		},
+		AdvisoryLock: map[string]int64{"lock0": 1, "lock1": 1},
+	})
+	released := sqltypes.MakeTestResult(sqltypes.MakeTestFields("release_all_locks()", "int64"), "1")
+	sbc0.SetResults([]*sqltypes.Result{released})
+	sbc1.SetResults([]*sqltypes.Result{released})
+
+	count, err := sc.txConn.ReleaseAllEverywhere(ctx, session)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.Nil(t, session.AdvisoryLock)
+	releaseAll := []*querypb.BoundQuery{{
+		Sql:           "select release_all_locks() from dual",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}}
+	utils.MustMatch(t, releaseAll, sbc0.Queries, "")
+	utils.MustMatch(t, releaseAll, sbc1.Queries, "")
+
+	// the reserved connections themselves are left to ReleaseAll.
+	assert.EqualValues(t, 1, session.ShardSessions[0].ReservedId)
+	assert.EqualValues(t, 2, session.LockSession.ReservedId)
+}
+
+func TestTxConnReleaseLockCleanup(t *testing.T) {
+	defer func(query string) { *lockConnCleanupQuery = query }(*lockConnCleanupQuery)
+	sc, _, sbc1, _, rss1, _ := newTestTxConnEnv(t, "TestTxConn")
+	newSession := func() *SafeSession {
+		return NewSafeSession(&vtgatepb.Session{
+			LockSession: &vtgatepb.Session_ShardSession{
+				Target:      rss1[0].Target,
+				TabletAlias: sbc1.Tablet().Alias,
+				ReservedId:  2,
+			},
+		})
+	}
+
+	// disabled by default: the connection is released right away.
+	require.NoError(t, sc.txConn.ReleaseLock(ctx, newSession()))
+	assert.Empty(t, sbc1.Queries)
+	assert.EqualValues(t, 1, sbc1.ReleaseCount.Get())
+
+	*lockConnCleanupQuery = "set session sql_mode = default"
+	cleanup := []*querypb.BoundQuery{{
+		Sql:           "set session sql_mode = default",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}}
+	require.NoError(t, sc.txConn.ReleaseLock(ctx, newSession()))
+	utils.MustMatch(t, cleanup, sbc1.Queries, "")
+	assert.EqualValues(t, 2, sbc1.ReleaseCount.Get())
+
+	// the lock connection is cleaned up as well when the whole session is released.
+	sbc1.Queries = nil
+	require.NoError(t, sc.txConn.ReleaseAll(ctx, newSession()))
+	utils.MustMatch(t, cleanup, sbc1.Queries, "")
+	assert.EqualValues(t, 3, sbc1.ReleaseCount.Get())
+
+	// a failed cleanup does not prevent the release.
+	sbc1.Queries = nil
+	sbc1.MustFailCodes[vtrpcpb.Code_INTERNAL] = 1
+	require.NoError(t, sc.txConn.ReleaseLock(ctx, newSession()))
+	assert.EqualValues(t, 4, sbc1.ReleaseCount.Get())
+}
@@ -87,6 +87,7 @@ type vcursorImpl struct {
 	destination    key.Destination
 	marginComments sqlparser.MarginComments
 	executor       iExecute
+	hooks          *hooks
 	resolver       *srvtopo.Resolver
 	topoServer     *topo.Server
 	logStats       *LogStats
@@ -95,6 +96,7 @@ type vcursorImpl struct {
 	// must be forced to rollback.
 	rollbackOnPartialExec bool
 	ignoreMaxMemoryRows   bool
+	dryRun                bool
 	vschema               *vindexes.VSchema
 	vm                    VSchemaOperator
 }
@@ -162,6 +164,10 @@ func newVCursorImpl(ctx context.Context, safeSession *SafeSession, marginComment
 		}
 	}
 
+	var h *hooks
+	if executor != nil {
+		h = executor.hooks
+	}
 	return &vcursorImpl{
 		ctx:            ctx,
 		safeSession:    safeSession,
@@ -170,6 +176,7 @@ func newVCursorImpl(ctx context.Context, safeSession *SafeSession, marginComment
 		destination:    destination,
 		marginComments: marginComments,
 		executor:       executor,
+		hooks:          h,
 		logStats:       logStats,
 		resolver:       resolver,
 		vschema:        vschema,
@@ -194,6 +201,52 @@ func (vc *vcursorImpl) ExceedsMaxMemoryRows(numRows int) bool {
 	return !vc.ignoreMaxMemoryRows && numRows > *maxMemoryRows
 }
 
+// MaxAdvisoryLocks returns the max_advisory_locks_per_session flag value.
+func (vc *vcursorImpl) MaxAdvisoryLocks() int {
+	return *maxAdvisoryLocks
+}
+
+// MaxLockWait returns the max_advisory_lock_wait flag value.
+func (vc *vcursorImpl) MaxLockWait() time.Duration {
+	return *maxLockWait
+}
+
+// ReservedConnEnabled returns the enable_reserved_connections flag value.
+func (vc *vcursorImpl) ReservedConnEnabled() bool {
+	return *reservedConnEnabled
+}
+
+// AdvisoryLocksEnabled returns the enable_advisory_locks flag value.
+// It implements both the VCursor and the ContextVSchema interfaces.
+func (vc *vcursorImpl) AdvisoryLocksEnabled() bool {
+	return *advisoryLocksEnabled
+}
+
+// VtgateExplainAnalyzeEnabled returns the enable_vtgate_explain_analyze flag value.
+func (vc *vcursorImpl) VtgateExplainAnalyzeEnabled() bool {
+	return *vtgateExplainAnalyze
+}
+
+// ReservedConnID implements the VCursor interface
+func (vc *vcursorImpl) ReservedConnID() int64 {
+	return vc.safeSession.LockSessionReservedID()
+}
+
+// DryRun implements the VCursor interface
+func (vc *vcursorImpl) DryRun() bool {
+	return vc.dryRun
+}
+
+// OnLockEvent implements the VCursor interface
+func (vc *vcursorImpl) OnLockEvent(event engine.LockEvent) {
+	vc.hooks.onLockEvent(vc.ctx, event)
+}
+
+// SetDryRun makes the primitives only route and validate their queries.
+func (vc *vcursorImpl) SetDryRun(dryRun bool) {
+	vc.dryRun = dryRun
+}
+
 // SetIgnoreMaxMemoryRows sets the ignoreMaxMemoryRows value.
 func (vc *vcursorImpl) SetIgnoreMaxMemoryRows(ignoreMaxMemoryRows bool) {
 	vc.ignoreMaxMemoryRows = ignoreMaxMemoryRows
@@ -206,6 +259,15 @@ func (vc *vcursorImpl) SetContextTimeout(timeout time.Duration) context.CancelFu
 	return cancel
 }
 
+// SetContext makes the cursor use the given context, until the returned function restores the previous one.
+func (vc *vcursorImpl) SetContext(ctx context.Context) func() {
+	origCtx := vc.ctx
+	vc.ctx = ctx
+	return func() {
+		vc.ctx = origCtx
+	}
+}
+
 // ErrorGroupCancellableContext updates context that can be cancelled.
 func (vc *vcursorImpl) ErrorGroupCancellableContext() (*errgroup.Group, func()) {
 	origCtx := vc.ctx
@@ -377,6 +439,11 @@ func (vc *vcursorImpl) ExecuteMultiShard(rss []*srvtopo.ResolvedShard, queries [
 	return qr, errs
 }
 
+// InTransaction implements the VCursor interface
+func (vc *vcursorImpl) InTransaction() bool {
+	return vc.safeSession.InTransaction()
+}
+
 func (vc *vcursorImpl) InTransactionAndIsDML() bool {
 	if !vc.safeSession.InTransaction() {
 		return false
@@ -396,11 +463,28 @@ func (vc *vcursorImpl) LookupRowLockShardSession() vtgatepb.CommitOrder {
 	return vtgatepb.CommitOrder_PRE
 }
 
+// lockLogNow is the clock timing the lock queries for the query log. It is time.Now outside of tests.
+var lockLogNow = time.Now
+
 func (vc *vcursorImpl) ExecuteLock(rs *srvtopo.ResolvedShard, query *querypb.BoundQuery) (*sqltypes.Result, error) {
 	query.Sql = vc.marginComments.Leading + query.Sql + vc.marginComments.Trailing
+	start := lockLogNow()
+	defer func() {
+		atomic.AddInt64((*int64)(&vc.logStats.LockTime), int64(lockLogNow().Sub(start)))
+	}()
 	return vc.executor.ExecuteLock(vc.ctx, rs, query, vc.safeSession)
 }
 
+// SetLockWaitTimeout is part of the engine.VCursor interface.
+func (vc *vcursorImpl) SetLockWaitTimeout(rs *srvtopo.ResolvedShard, timeout time.Duration) error {
+	seconds := int64(timeout / time.Second)
+	if timeout%time.Second != 0 {
+		seconds++
+	}
+	_, err := vc.ExecuteLock(rs, &querypb.BoundQuery{Sql: fmt.Sprintf("set @@lock_wait_timeout = %d", seconds)})
+	return err
+}
+
 // AutocommitApproval is part of the engine.VCursor interface.
 func (vc *vcursorImpl) AutocommitApproval() bool {
 	return vc.safeSession.AutocommitApproval()
@@ -450,7 +534,12 @@ func (vc *vcursorImpl) ExecuteKeyspaceID(keyspace string, ksid []byte, query str
 }
 
 func (vc *vcursorImpl) ResolveDestinations(keyspace string, ids []*querypb.Value, destinations []key.Destination) ([]*srvtopo.ResolvedShard, [][]*querypb.Value, error) {
-	return vc.resolver.ResolveDestinations(vc.ctx, keyspace, vc.tabletType, ids, destinations)
+	rss, values, err := vc.resolver.ResolveDestinations(vc.ctx, keyspace, vc.tabletType, ids, destinations)
+	if err != nil {
+		return nil, nil, err
+	}
+	vc.hooks.onResolve(vc.ctx, keyspace, destinations, rss)
+	return rss, values, nil
 }
 
 func (vc *vcursorImpl) Session() engine.SessionActions {
@@ -514,6 +603,36 @@ func (vc *vcursorImpl) ShardSession() []*srvtopo.ResolvedShard {
 	return rss
 }
 
+// TargetReadOnly implements the VCursor interface
+func (vc *vcursorImpl) TargetReadOnly(target *querypb.Target) bool {
+	if vc.resolver == nil {
+		return false
+	}
+	gw, ok := vc.resolver.GetGateway().(Gateway)
+	if !ok {
+		return false
+	}
+	// the legacy gateway does not expose the serving state of its tablets.
+	known := false
+	for _, status := range gw.TabletsCacheStatus() {
+		if status.Target.Keyspace != target.Keyspace || status.Target.Shard != target.Shard || status.Target.TabletType != target.TabletType {
+			continue
+		}
+		for _, th := range status.TabletsStats {
+			if th.Serving {
+				return false
+			}
+			known = true
+		}
+	}
+	return known
+}
+
+// SessionDestination implements the VCursor interface
+func (vc *vcursorImpl) SessionDestination() (string, key.Destination) {
+	return vc.keyspace, vc.destination
+}
+
 // Destination implements the ContextVSchema interface
 func (vc *vcursorImpl) Destination() key.Destination {
 	return vc.destination
@@ -619,6 +738,47 @@ func (vc *vcursorImpl) GetDDLStrategy() string {
 	return vc.safeSession.GetDDLStrategy()
 }
 
+// SetReleaseLocksOnCommit implements the SessionActions interface
+func (vc *vcursorImpl) SetReleaseLocksOnCommit(release bool) error {
+	vc.safeSession.SetReleaseLocksOnCommit(release)
+	return nil
+}
+
+// AdvisoryLockCount implements the SessionActions interface
+func (vc *vcursorImpl) AdvisoryLockCount(name string) int64 {
+	return vc.safeSession.AdvisoryLockCount(name)
+}
+
+// AdvisoryLocksHeld implements the SessionActions interface
+func (vc *vcursorImpl) AdvisoryLocksHeld() int {
+	return vc.safeSession.AdvisoryLocksHeld()
+}
+
+// AdvisoryLocks implements the SessionActions interface
+func (vc *vcursorImpl) AdvisoryLocks() map[string]int64 {
+	return vc.safeSession.AdvisoryLocks()
+}
+
+// LockSessionTarget implements the SessionActions interface
+func (vc *vcursorImpl) LockSessionTarget() *querypb.Target {
+	return vc.safeSession.LockSessionTarget()
+}
+
+// SetAdvisoryLockCount implements the SessionActions interface
+func (vc *vcursorImpl) SetAdvisoryLockCount(name string, count int64) {
+	vc.safeSession.SetAdvisoryLockCount(name, count)
+}
+
+// ResetAdvisoryLocks implements the SessionActions interface
+func (vc *vcursorImpl) ResetAdvisoryLocks() {
+	vc.safeSession.ResetAdvisoryLocks()
+}
+
+// SessionKey implements the SessionActions interface
+func (vc *vcursorImpl) SessionKey() interface{} {
+	return vc.safeSession.LockKey()
+}
+
 // SetReadAfterWriteGTID implements the SessionActions interface
 func (vc *vcursorImpl) SetReadAfterWriteGTID(vtgtid string) {
 	vc.safeSession.SetReadAfterWriteGTID(vtgtid)
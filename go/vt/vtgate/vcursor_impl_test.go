@@ -5,13 +5,18 @@ import (
 	"encoding/hex"
 	"fmt"
 	"testing"
+	"time"
 
 	"vitess.io/vitess/go/vt/proto/vschema"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo"
 
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
 	"github.com/stretchr/testify/require"
@@ -306,3 +311,30 @@ func TestFirstSortedKeyspace(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, ks3Schema.Keyspace, ks)
 }
+
+func TestSetLockWaitTimeout(t *testing.T) {
+	executor, sbc1, _, _ := createExecutorEnv()
+	session := NewSafeSession(nil)
+	vc, err := newVCursorImpl(ctx, session, makeComments(""), executor, NewLogStats(ctx, "Test", "", nil), executor.vm, executor.VSchema(), executor.resolver.resolver, nil)
+	require.NoError(t, err)
+
+	l, err := engine.NewLock(executor.VSchema().Keyspaces["TestExecutor"].Keyspace, key.DestinationShard("-20"), "select get_lock('lock name', 10) from dual",
+		engine.WithLockWaitTimeout(1500*time.Millisecond),
+		engine.WithLockFuncs(engine.LockFunc{Type: engine.GetLock, Name: sqltypes.PlanValue{Value: sqltypes.NewVarChar("lock name")}}))
+	require.NoError(t, err)
+	_, err = l.Execute(vc, nil, false)
+	require.NoError(t, err)
+
+	// the timeout is set on the reserved connection, then the lock is acquired on it.
+	wantQueries := []*querypb.BoundQuery{{
+		Sql:           "set @@lock_wait_timeout = 2",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}, {
+		Sql:           "select get_lock('lock name', 10) from dual",
+		BindVariables: map[string]*querypb.BindVariable{},
+	}}
+	utils.MustMatch(t, wantQueries, sbc1.Queries, "")
+	require.EqualValues(t, 1, sbc1.ReserveCount.Get())
+	require.NotNil(t, session.LockSession)
+	require.EqualValues(t, 1, session.LockSession.ReservedId)
+}
@@ -96,6 +96,8 @@ type Table struct {
 type Keyspace struct {
 	Name    string
 	Sharded bool
+	// AdvisoryLocksDenied rejects the advisory locks in the keyspace.
+	AdvisoryLocksDenied bool `json:",omitempty"`
 }
 
 // ColumnVindex contains the index info for each index of a table.
@@ -46,7 +46,12 @@ func (vm *VSchemaManager) GetCurrentVschema() (*vindexes.VSchema, error) {
 	if srvVschema == nil {
 		return nil, nil
 	}
-	return vindexes.BuildVSchema(srvVschema)
+	vschema, err := vindexes.BuildVSchema(srvVschema)
+	if err != nil {
+		return nil, err
+	}
+	applyAdvisoryLockPolicy(vschema)
+	return vschema, nil
 }
 
 // GetCurrentSrvVschema returns a copy of the latest SrvVschema from the
@@ -108,6 +113,7 @@ func (vm *VSchemaManager) watchSrvVSchema(ctx context.Context, cell string) {
 			// We encountered an error, build an empty vschema.
 			vschema, _ = vindexes.BuildVSchema(&vschemapb.SrvVSchema{})
 		}
+		applyAdvisoryLockPolicy(vschema)
 
 		// Build the display version. At this point, three cases:
 		// - v is nil, vschema is empty, and err is set:
@@ -42,6 +42,7 @@ import (
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
 
@@ -76,6 +77,24 @@ var (
 	sysVarSetEnabled = flag.Bool("enable_system_settings", true, "This will enable the system settings to be changed per session at the database connection level")
 	// lockHeartbeatTime is used to set the next heartbeat time.
 	lockHeartbeatTime = flag.Duration("lock_heartbeat_time", 5*time.Second, "If there is lock function used. This will keep the lock connection active by using this heartbeat")
+	// maxAdvisoryLocks is the maximum number of advisory locks a session can hold.
+	maxAdvisoryLocks = flag.Int("max_advisory_locks_per_session", 0, "Maximum number of advisory locks a session can hold at the same time. 0 means no limit.")
+	// maxLockWait is the ceiling of the time a lock query can wait for its locks.
+	maxLockWait = flag.Duration("max_advisory_lock_wait", 0, "Maximum time a lock query can wait for its advisory locks. Longer or infinite GET_LOCK timeouts are lowered to it. 0 means no limit.")
+	// reservedConnEnabled allows the queries that need a reserved connection on the tablets, like advisory locks.
+	reservedConnEnabled = flag.Bool("enable_reserved_connections", true, "If false, the queries that need a reserved connection on the tablets, like advisory locks, are rejected")
+	// advisoryLocksEnabled allows the advisory lock functions in queries.
+	advisoryLocksEnabled = flag.Bool("enable_advisory_locks", true, "If false, the queries using advisory lock functions, like GET_LOCK, are rejected")
+	// vtgateExplainAnalyze makes vtgate execute EXPLAIN ANALYZE instead of the tablets.
+	vtgateExplainAnalyze = flag.Bool("enable_vtgate_explain_analyze", false, "If true, EXPLAIN ANALYZE of a select is executed by vtgate, which reports the rows and time of every primitive of its plan, instead of being sent to a tablet")
+	// lockConnCleanupQuery is sent to the lock connection before it is released.
+	lockConnCleanupQuery = flag.String("lock_connection_cleanup_query", "", "If set, this statement is sent to the reserved connection holding the advisory locks before it is released, so that no session state is left on it. Disabled by default.")
+	// reservedConnPrecheck rejects the plans needing a reserved connection before they start executing.
+	reservedConnPrecheck = flag.Bool("reserved_connections_precheck", false, "If true and reserved connections are disabled, a plan that needs one anywhere in its tree is rejected before any of its queries is executed")
+	// maxPlanMemory is the budget of the estimated memory of a plan.
+	maxPlanMemory = flag.Int64("max_plan_memory", 0, "If set, the plans estimated to use more memory than this number of bytes, like the ones sorting or joining rows in vtgate, are rejected before they start executing. 0 means no limit.")
+	// lockMetricsNameBuckets bounds the cardinality of the lock contention metrics.
+	lockMetricsNameBuckets = flag.Int("lock_metrics_name_buckets", 0, "If set, the lock names are hashed into this number of buckets in the lock contention metrics. 0 means the lock names are used as they are.")
 )
 
 func getTxMode() vtgatepb.TransactionMode {
@@ -170,6 +189,8 @@ func Init(ctx context.Context, serv srvtopo.Server, cell string, tabletTypesToWa
 	if _, _, err := schema.ParseDDLStrategy(*defaultDDLStrategy); err != nil {
 		log.Fatalf("Invalid value for -ddl_strategy: %v", err.Error())
 	}
+	engine.LockMetricsNameBuckets = *lockMetricsNameBuckets
+
 	tc := NewTxConn(gw, getTxMode())
 	// ScatterConn depends on TxConn to perform forced rollbacks.
 	sc := NewScatterConn("VttabletCall", tc, gw)
@@ -251,6 +272,11 @@ func (vtg *VTGate) Gateway() Gateway {
 	return vtg.gw
 }
 
+// Executor returns the executor of the VTGate, e.g. to register hooks on it.
+func (vtg *VTGate) Executor() *Executor {
+	return vtg.executor
+}
+
 // Execute executes a non-streaming query. This is a V3 function.
 func (vtg *VTGate) Execute(ctx context.Context, session *vtgatepb.Session, sql string, bindVariables map[string]*querypb.BindVariable) (newSession *vtgatepb.Session, qr *sqltypes.Result, err error) {
 	// In this context, we don't care if we can't fully parse destination